@@ -0,0 +1,83 @@
+package scan_test
+
+import (
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type arrayPost struct {
+	ID     int       `db:"id"`
+	Tags   []string  `db:"tags,array"`
+	Scores [][]int64 `db:"scores,array"`
+	Refs   *[]int64  `db:"refs,array"`
+}
+
+func TestRowsScansArrayColumns(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "tags", "scores", "refs"},
+		[]interface{}{1, []byte(`{go,"sql, scan"}`), []byte(`{{1,2},{3,4}}`), []byte(`{7}`)},
+		[]interface{}{2, nil, nil, nil},
+	)
+
+	var posts []arrayPost
+	require.NoError(t, scan.Rows(&posts, rows))
+	assert.Equal(t, []arrayPost{
+		{ID: 1, Tags: []string{"go", "sql, scan"}, Scores: [][]int64{{1, 2}, {3, 4}}, Refs: &[]int64{7}},
+		{ID: 2},
+	}, posts)
+}
+
+func TestScannerPostgresArraysScansEverySlice(t *testing.T) {
+	cfg := scan.DefaultConfig()
+	cfg.PostgresArrays = true
+	s := scan.New(cfg)
+
+	var item struct {
+		IDs  []int64 `db:"ids"`
+		Data []byte  `db:"data"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"ids", "data"},
+		[]interface{}{[]byte(`{1,2}`), []byte(`{1,2}`)},
+	)
+
+	require.NoError(t, s.Row(&item, rows))
+	assert.Equal(t, []int64{1, 2}, item.IDs)
+	assert.Equal(t, []byte(`{1,2}`), item.Data)
+}
+
+func TestRowReturnsArrayErrors(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"tags"},
+		[]interface{}{[]byte(`{a`)},
+	)
+
+	var post arrayPost
+	err := scan.Row(&post, rows)
+	var se *scan.ScanError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, "Tags", se.Field)
+}
+
+func TestValuesFormatsArrays(t *testing.T) {
+	post := arrayPost{ID: 1, Tags: []string{"go", `"quoted"`}, Scores: [][]int64{{1}}}
+
+	vals, err := scan.Values([]string{"id", "tags", "scores", "refs"}, &post)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1, `{"go","\"quoted\""}`, `{{1}}`, nil}, vals)
+}
+
+func TestScannerPostgresArraysFormatsValues(t *testing.T) {
+	cfg := scan.DefaultConfig()
+	cfg.PostgresArrays = true
+	s := scan.New(cfg)
+
+	item := struct {
+		IDs []int64 `db:"ids"`
+	}{IDs: []int64{1, 2}}
+
+	vals, err := s.Values([]string{"ids"}, &item)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{`{1,2}`}, vals)
+}
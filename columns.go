@@ -48,17 +48,29 @@ type cacheKey struct {
 // tag that matches a string within the excluded list
 // will be excluded from the result.
 func Columns(v interface{}, excluded ...string) ([]string, error) {
-	return columns(v, false, excluded...)
+	return std().Columns(v, excluded...)
 }
 
 // ColumnsStrict is identical to Columns, but it only
 // searches struct tags and excludes fields not tagged
 // with the db struct tag.
 func ColumnsStrict(v interface{}, excluded ...string) ([]string, error) {
-	return columns(v, true, excluded...)
+	return std().ColumnsStrict(v, excluded...)
 }
 
-func columns(v interface{}, strict bool, excluded ...string) ([]string, error) {
+// Columns scans a struct and returns a list of strings that represent the
+// assumed column names. See Columns for details.
+func (s *Scanner) Columns(v interface{}, excluded ...string) ([]string, error) {
+	return s.columnsOf(v, s.cfg.Strict, excluded...)
+}
+
+// ColumnsStrict is identical to Columns, but it only searches struct tags and
+// excludes fields not tagged with the configured tag name.
+func (s *Scanner) ColumnsStrict(v interface{}, excluded ...string) ([]string, error) {
+	return s.columnsOf(v, true, excluded...)
+}
+
+func (s *Scanner) columnsOf(v interface{}, strict bool, excluded ...string) ([]string, error) {
 	model, err := reflectValue(v)
 	if err != nil {
 		return nil, fmt.Errorf("columns: %w", err)
@@ -66,7 +78,7 @@ func columns(v interface{}, strict bool, excluded ...string) ([]string, error) {
 
 	key := cacheKey{model.Type(), strict}
 
-	if cache, ok := columnsCache.Load(key); ok {
+	if cache, ok := s.columns.Load(key); ok {
 		cached := cache.([]string)
 		res := make([]string, 0, len(cached))
 
@@ -87,13 +99,13 @@ func columns(v interface{}, strict bool, excluded ...string) ([]string, error) {
 		return res, nil
 	}
 
-	names := columnNames(model, strict, excluded...)
+	names := s.columnNames(model, strict, "", nil, excluded...)
 	toCache := append(names, excluded...)
-	columnsCache.Store(key, toCache)
+	s.columns.Store(key, toCache)
 	return names, nil
 }
 
-func columnNames(model reflect.Value, strict bool, excluded ...string) []string {
+func (s *Scanner) columnNames(model reflect.Value, strict bool, prefix string, hops []reflect.Type, excluded ...string) []string {
 	numfield := model.NumField()
 	names := make([]string, 0, numfield)
 
@@ -104,19 +116,32 @@ func columnNames(model reflect.Value, strict bool, excluded ...string) []string
 		}
 
 		typeField := model.Type().Field(i)
+		isLeaf := s.converters.isLeaf(typeField.Type) || s.isJSON(typeField)
 
-		if typeField.Type.Kind() == reflect.Struct && !isValidSqlValue(valField) {
-			embeddedNames := columnNames(valField, strict, excluded...)
+		if typeField.Type.Kind() == reflect.Struct && !isValidSqlValue(valField) && !isLeaf {
+			childPrefix, _ := s.prefix(typeField)
+			embeddedNames := s.columnNames(valField, strict, prefix+childPrefix, hops, excluded...)
 			names = append(names, embeddedNames...)
 			continue
 		}
 
-		fieldName := ColumnsMapper(typeField.Name)
-		if tag, hasTag := typeField.Tag.Lookup(dbTag); hasTag {
-			if tag == "-" {
+		if isPointerStruct(typeField.Type, hops) && !isLeaf {
+			childPrefix, _ := s.prefix(typeField)
+			elem := reflect.New(typeField.Type.Elem()).Elem()
+			embeddedNames := s.columnNames(elem, strict, prefix+childPrefix, appendHop(hops, typeField.Type), excluded...)
+			names = append(names, embeddedNames...)
+			continue
+		}
+
+		fieldName := prefix + s.cfg.ColumnsMapper(typeField.Name)
+		if tag, hasTag := typeField.Tag.Lookup(s.cfg.TagName); hasTag {
+			name, _ := parseTag(tag)
+			if name == "-" {
 				continue
 			}
-			fieldName = tag
+			if name != "" {
+				fieldName = prefix + name
+			}
 		} else if strict {
 			// there's no tag name and we're in strict mode so move on
 			continue
@@ -126,7 +151,7 @@ func columnNames(model reflect.Value, strict bool, excluded ...string) []string
 			continue
 		}
 
-		if supportedColumnType(valField) || isValidSqlValue(valField) {
+		if supportedColumnType(valField) || isValidSqlValue(valField) || isLeaf {
 			names = append(names, fieldName)
 		}
 	}
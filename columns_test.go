@@ -326,6 +326,35 @@ func TestColumnsWorkWithPointerValidSqlTypes(t *testing.T) {
 	assert.EqualValues(t, []string{"value", "expires"}, cols)
 }
 
+func TestColumnsIgnoresTagOptions(t *testing.T) {
+	type person struct {
+		ID      int `db:"id"`
+		Company struct {
+			ID int `db:"id,occurrence=2"`
+		}
+		Name string `db:",occurrence=1"`
+	}
+
+	cols, err := Columns(&person{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"id", "id", "Name"}, cols)
+}
+
+func TestColumnsIncludesPointerStructs(t *testing.T) {
+	type employee struct {
+		ID      int       `db:"id"`
+		Manager *employee `db:"manager_,prefix"`
+		Company *struct {
+			Name string `db:"company.name"`
+		}
+		Hired *time.Time `db:"hired"`
+	}
+
+	cols, err := Columns(&employee{})
+	assert.NoError(t, err)
+	assert.EqualValues(t, []string{"id", "manager_id", "manager_company.name", "manager_hired", "company.name", "hired"}, cols)
+}
+
 type Pet struct {
 	Species string
 	Name    string
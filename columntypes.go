@@ -0,0 +1,124 @@
+package scan
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"reflect"
+	"strings"
+	"time"
+)
+
+var (
+	anyType         = reflect.TypeOf((*interface{})(nil)).Elem()
+	bytesType       = reflect.TypeOf([]byte(nil))
+	timeType        = reflect.TypeOf(time.Time{})
+	nullBoolType    = reflect.TypeOf(sql.NullBool{})
+	nullFloat64Type = reflect.TypeOf(sql.NullFloat64{})
+	nullInt64Type   = reflect.TypeOf(sql.NullInt64{})
+	nullStringType  = reflect.TypeOf(sql.NullString{})
+	nullTimeType    = reflect.TypeOf(sql.NullTime{})
+	sqlScannerType  = reflect.TypeOf((*sql.Scanner)(nil)).Elem()
+	valuerType      = reflect.TypeOf((*driver.Valuer)(nil)).Elem()
+)
+
+// columnValue scans a column whose Go type is not known in advance into a
+// sensible Go value based on its column type. NULL always results in nil.
+type columnValue struct {
+	// typ is the type that the column is scanned into
+	typ reflect.Type
+	// text converts []byte values into strings
+	text bool
+}
+
+// columnValues returns a columnValue for each of the n columns of r. Columns
+// without type information are scanned as whatever the driver returns.
+func columnValues(r RowsScanner, n int) ([]columnValue, error) {
+	types, err := r.ColumnTypes()
+	if err != nil {
+		return nil, err
+	}
+
+	vals := make([]columnValue, n)
+	for i := range vals {
+		if i < len(types) && types[i] != nil {
+			vals[i] = columnValueOf(types[i].ScanType(), types[i].DatabaseTypeName())
+		} else {
+			vals[i] = columnValue{typ: anyType}
+		}
+	}
+	return vals, nil
+}
+
+func columnValueOf(scanType reflect.Type, dbType string) columnValue {
+	text := isTextType(dbType)
+	if scanType == nil {
+		return columnValue{typ: anyType, text: text}
+	}
+
+	switch scanType {
+	case nullBoolType, nullFloat64Type, nullInt64Type, nullStringType, nullTimeType:
+		return columnValue{typ: scanType}
+	case timeType:
+		return columnValue{typ: nullTimeType}
+	}
+
+	switch scanType.Kind() {
+	case reflect.Bool:
+		return columnValue{typ: nullBoolType}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return columnValue{typ: nullInt64Type}
+	case reflect.Float32, reflect.Float64:
+		return columnValue{typ: nullFloat64Type}
+	case reflect.String:
+		return columnValue{typ: nullStringType}
+	case reflect.Slice:
+		if scanType.Elem().Kind() == reflect.Uint8 {
+			if text {
+				return columnValue{typ: nullStringType}
+			}
+			return columnValue{typ: bytesType}
+		}
+	}
+	return columnValue{typ: anyType, text: text}
+}
+
+// isTextType reports whether a database type name describes a column that
+// holds text, for which drivers commonly return []byte.
+func isTextType(dbType string) bool {
+	dbType = strings.ToUpper(dbType)
+	for _, t := range []string{"CHAR", "TEXT", "CLOB", "JSON", "XML", "UUID", "ENUM", "DECIMAL", "NUMERIC"} {
+		if strings.Contains(dbType, t) {
+			return true
+		}
+	}
+	return false
+}
+
+// dest returns a new pointer to scan the column into.
+func (c columnValue) dest() interface{} {
+	return reflect.New(c.typ).Interface()
+}
+
+// value returns the Go value of a dest after it has been scanned.
+func (c columnValue) value(dest interface{}) interface{} {
+	switch d := dest.(type) {
+	case *[]byte:
+		if *d == nil {
+			return nil
+		}
+		return *d
+	case *interface{}:
+		if b, ok := (*d).([]byte); ok && c.text {
+			return string(b)
+		}
+		return *d
+	case driver.Valuer:
+		v, err := d.Value()
+		if err != nil {
+			return nil
+		}
+		return v
+	}
+	return reflect.ValueOf(dest).Elem().Interface()
+}
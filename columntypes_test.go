@@ -0,0 +1,43 @@
+package scan
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestColumnValueOf(t *testing.T) {
+	table := []struct {
+		scanType reflect.Type
+		dbType   string
+		exp      columnValue
+	}{
+		{reflect.TypeOf(int32(0)), "INT", columnValue{typ: nullInt64Type}},
+		{reflect.TypeOf(uint8(0)), "TINYINT", columnValue{typ: nullInt64Type}},
+		{reflect.TypeOf(float32(0)), "FLOAT", columnValue{typ: nullFloat64Type}},
+		{reflect.TypeOf(""), "TEXT", columnValue{typ: nullStringType}},
+		{reflect.TypeOf(false), "BOOL", columnValue{typ: nullBoolType}},
+		{reflect.TypeOf(time.Time{}), "TIMESTAMP", columnValue{typ: nullTimeType}},
+		{reflect.TypeOf(sql.NullInt64{}), "BIGINT", columnValue{typ: nullInt64Type}},
+		{reflect.TypeOf(sql.RawBytes{}), "VARCHAR", columnValue{typ: nullStringType}},
+		{reflect.TypeOf(sql.RawBytes{}), "DECIMAL", columnValue{typ: nullStringType}},
+		{reflect.TypeOf(sql.RawBytes{}), "BLOB", columnValue{typ: bytesType}},
+		{anyType, "JSON", columnValue{typ: anyType, text: true}},
+		{anyType, "", columnValue{typ: anyType}},
+		{nil, "", columnValue{typ: anyType}},
+	}
+
+	for _, tt := range table {
+		assert.Equal(t, tt.exp, columnValueOf(tt.scanType, tt.dbType), "%v %s", tt.scanType, tt.dbType)
+	}
+}
+
+func TestColumnValueConvertsTextBytes(t *testing.T) {
+	var raw interface{} = []byte("brett")
+
+	assert.Equal(t, "brett", columnValue{typ: anyType, text: true}.value(&raw))
+	assert.Equal(t, []byte("brett"), columnValue{typ: anyType}.value(&raw))
+}
@@ -0,0 +1,177 @@
+package scan
+
+import (
+	"reflect"
+	"sync"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/language"
+)
+
+// Config holds the settings of a Scanner. Use DefaultConfig to start from the
+// settings used by the package-level functions.
+type Config struct {
+	// TagName is the struct tag used to map fields to columns. It defaults to
+	// "db" when empty.
+	TagName string
+
+	// Strict makes Row, Rows and Columns behave like RowStrict, RowsStrict and
+	// ColumnsStrict, ignoring fields that are not tagged with TagName.
+	Strict bool
+
+	// ErrorOnUnmappedColumns makes scanning into structs fail with an error
+	// wrapping ErrStructFieldMissing when a column has no destination field,
+	// instead of silently discarding the column.
+	ErrorOnUnmappedColumns bool
+
+	// ErrorOnUnfilledFields makes scanning into structs fail with an error
+	// wrapping ErrStructFieldMissing when a field tagged with TagName has no
+	// column, instead of leaving the field at its zero value.
+	ErrorOnUnfilledFields bool
+
+	// DisableAutoClose stops the scanner from closing the RowsScanner when
+	// the scan is complete, in which case you must defer rows.Close()
+	// manually. Rows are closed by default, like with the package-level
+	// functions, so that a zero Config does not leak connections.
+	DisableAutoClose bool
+
+	// OnAutoCloseError can be used to log errors which are returned from
+	// rows.Close() when DisableAutoClose is false.
+	OnAutoCloseError func(error)
+
+	// ScannerMapper transforms database column names into struct field names.
+	// It defaults to title casing the column name.
+	ScannerMapper func(string) string
+
+	// ColumnsMapper transforms struct field names into database column names.
+	// It defaults to using the field name unchanged.
+	ColumnsMapper func(string) string
+
+	// PostgresArrays makes every slice field without a converter scan from
+	// and format to the Postgres array text format, as if it was tagged with
+	// the array option.
+	PostgresArrays bool
+
+	// NullZero makes every field and primitive value which is not a pointer
+	// or an interface scan NULL as its zero value. Unlike the nullzero tag
+	// option, it does not make Values return NULL for zero values, which
+	// would write NULL for every false and 0.
+	NullZero bool
+
+	// Lenient makes Rows, RowsGrouped, RowsMap, RowsGroup, Func, AllWith,
+	// EachWith, IterWith and the slices of ResultSets skip the rows which
+	// fail to scan instead of stopping at the first one. The errors of the
+	// skipped rows are returned in a *ScanErrors once the other rows are
+	// scanned, which for ResultSets covers every result set. Row and its
+	// variants are not affected.
+	Lenient bool
+
+	// MaxErrors is the number of rows which can fail to scan in Lenient mode
+	// before scanning stops with a *ScanErrors wrapping ErrTooManyScanErrors.
+	// Zero means no limit.
+	MaxErrors int
+}
+
+// DefaultConfig returns the configuration currently used by the package-level
+// functions, which is derived from AutoClose, OnAutoCloseError, ScannerMapper
+// and ColumnsMapper.
+func DefaultConfig() Config {
+	return Config{
+		TagName:          dbTag,
+		DisableAutoClose: !AutoClose,
+		OnAutoCloseError: OnAutoCloseError,
+		ScannerMapper:    ScannerMapper,
+		ColumnsMapper:    ColumnsMapper,
+	}
+}
+
+// Scanner scans rows into structs, slices and primitive types using its own
+// configuration and caches. A Scanner is safe for concurrent use and should be
+// reused so that its caches are effective.
+type Scanner struct {
+	cfg        Config
+	columns    cache
+	values     cache
+	plans      cache
+	converters *converters
+	variants   *variants
+
+	// report collects the rows which fail to scan in Lenient mode across the
+	// result sets of ResultSets
+	report *ScanErrors
+}
+
+// New returns a Scanner using cfg. Empty TagName, ScannerMapper and
+// ColumnsMapper fields are replaced with their defaults. The Scanner starts
+// with the converters and variants registered with RegisterConverter,
+// RegisterValueConverter and RegisterVariant so far.
+func New(cfg Config) *Scanner {
+	if cfg.TagName == "" {
+		cfg.TagName = dbTag
+	}
+	if cfg.ScannerMapper == nil {
+		cfg.ScannerMapper = titleCase
+	}
+	if cfg.ColumnsMapper == nil {
+		cfg.ColumnsMapper = identity
+	}
+
+	return &Scanner{
+		cfg:        cfg,
+		columns:    &sync.Map{},
+		values:     &sync.Map{},
+		plans:      &sync.Map{},
+		converters: globalConverters.clone(),
+		variants:   globalVariants.clone(),
+	}
+}
+
+// Config returns the configuration of s.
+func (s *Scanner) Config() Config {
+	return s.cfg
+}
+
+// std returns the Scanner used by the package-level functions. It is built
+// from the package-level variables on every call so that changes to them take
+// effect immediately, while the caches are shared between calls. Plans depend
+// on ScannerMapper, which can be replaced at any time by a func that cannot be
+// told apart from the previous one, so they are only shared while it is the
+// default mapper.
+func std() *Scanner {
+	cfg := DefaultConfig()
+	if cfg.ScannerMapper == nil {
+		cfg.ScannerMapper = titleCase
+	}
+	if cfg.ColumnsMapper == nil {
+		cfg.ColumnsMapper = identity
+	}
+
+	plans := plansCache
+	if !isTitleCase(cfg.ScannerMapper) {
+		plans = &sync.Map{}
+	}
+
+	return &Scanner{
+		cfg:        cfg,
+		columns:    columnsCache,
+		values:     valuesCache,
+		plans:      plans,
+		converters: globalConverters,
+		variants:   globalVariants,
+	}
+}
+
+func titleCase(name string) string {
+	return cases.Title(language.English).String(name)
+}
+
+// isTitleCase reports whether mapper is titleCase. Comparing the code pointers
+// is reliable here because titleCase is not a closure, so no other func shares
+// its code.
+func isTitleCase(mapper func(string) string) bool {
+	return reflect.ValueOf(mapper).Pointer() == reflect.ValueOf(titleCase).Pointer()
+}
+
+func identity(name string) string {
+	return name
+}
@@ -0,0 +1,197 @@
+package scan_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewUsesDefaultsForEmptyFields(t *testing.T) {
+	cfg := scan.New(scan.Config{}).Config()
+
+	assert.Equal(t, "db", cfg.TagName)
+	require.NotNil(t, cfg.ScannerMapper)
+	require.NotNil(t, cfg.ColumnsMapper)
+	assert.Equal(t, "First", cfg.ScannerMapper("first"))
+	assert.Equal(t, "First", cfg.ColumnsMapper("First"))
+}
+
+func TestScannerUsesItsOwnMapper(t *testing.T) {
+	s := scan.New(scan.Config{
+		ScannerMapper: func(name string) string {
+			parts := strings.Split(name, "_")
+			for i, p := range parts {
+				parts[i] = strings.ToUpper(p[:1]) + p[1:]
+			}
+			return strings.Join(parts, "")
+		},
+	})
+
+	var item struct {
+		FirstName string
+	}
+	rows := fakeRowsWithRecords(t, []string{"first_name"},
+		[]interface{}{"Brett"},
+	)
+	require.NoError(t, s.Row(&item, rows))
+	assert.Equal(t, "Brett", item.FirstName)
+	assert.Equal(t, 1, rows.CloseCallCount(), "a zero Config closes rows")
+
+	// the package-level functions are unaffected
+	item.FirstName = ""
+	rows = fakeRowsWithRecords(t, []string{"first_name"},
+		[]interface{}{"Brett"},
+	)
+	require.NoError(t, scan.Row(&item, rows))
+	assert.Equal(t, "", item.FirstName)
+}
+
+func TestScannerUsesTagName(t *testing.T) {
+	s := scan.New(scan.Config{TagName: "sql"})
+
+	var items []struct {
+		Name string `sql:"full_name"`
+		Age  int    `db:"person_age"`
+	}
+	rows := fakeRowsWithRecords(t, []string{"full_name", "person_age"},
+		[]interface{}{"Brett", 40},
+	)
+	require.NoError(t, s.Rows(&items, rows))
+	require.Len(t, items, 1)
+	assert.Equal(t, "Brett", items[0].Name)
+	assert.Equal(t, 0, items[0].Age)
+}
+
+func TestScannerStrictConfig(t *testing.T) {
+	s := scan.New(scan.Config{Strict: true})
+
+	var item struct {
+		First string `db:"First"`
+		Last  string
+	}
+	rows := fakeRowsWithRecords(t, []string{"First", "Last"},
+		[]interface{}{"Brett", "Jones"},
+	)
+	require.NoError(t, s.Row(&item, rows))
+	assert.Equal(t, "Brett", item.First)
+	assert.Equal(t, "", item.Last)
+
+	cols, err := s.Columns(&item)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"First"}, cols)
+}
+
+func TestScannerAutoClose(t *testing.T) {
+	var name string
+
+	rows := fakeRowsWithRecords(t, []string{"name"}, []interface{}{"Bob"})
+	require.NoError(t, scan.New(scan.Config{DisableAutoClose: true}).Row(&name, rows))
+	assert.Equal(t, 0, rows.CloseCallCount())
+
+	rows = fakeRowsWithRecords(t, []string{"name"}, []interface{}{"Bob"})
+	require.NoError(t, scan.New(scan.Config{}).Row(&name, rows))
+	assert.Equal(t, 1, rows.CloseCallCount())
+}
+
+func TestScannerOnAutoCloseError(t *testing.T) {
+	var got error
+	s := scan.New(scan.Config{
+		OnAutoCloseError: func(err error) { got = err },
+	})
+
+	rows := fakeRowsWithRecords(t, []string{"name"}, []interface{}{"Bob"})
+	rows.CloseReturns(assert.AnError)
+
+	var name string
+	require.NoError(t, s.Row(&name, rows))
+	assert.Equal(t, assert.AnError, got)
+}
+
+func TestScannerColumnsAndValuesUseConfig(t *testing.T) {
+	s := scan.New(scan.Config{
+		TagName:       "sql",
+		ColumnsMapper: strings.ToLower,
+	})
+
+	item := struct {
+		ID   int `sql:"person_id"`
+		Name string
+	}{ID: 1, Name: "Brett"}
+
+	cols, err := s.Columns(&item)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"person_id", "name"}, cols)
+
+	vals, err := s.Values([]string{"person_id", "Name"}, &item)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1, "Brett"}, vals)
+
+	// the package-level cache is not shared with s
+	cols, err = scan.Columns(&item)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"ID", "Name"}, cols)
+}
+
+func TestDefaultConfigReflectsPackageVariables(t *testing.T) {
+	scan.AutoClose = false
+	defer func() { scan.AutoClose = true }()
+
+	cfg := scan.DefaultConfig()
+	assert.True(t, cfg.DisableAutoClose)
+	assert.Equal(t, "db", cfg.TagName)
+}
+
+func TestScannerErrorOnUnmappedColumns(t *testing.T) {
+	s := scan.New(scan.Config{ErrorOnUnmappedColumns: true})
+
+	var items []struct {
+		First string `db:"first"`
+	}
+	rows := fakeRowsWithRecords(t, []string{"first", "last"},
+		[]interface{}{"Brett", "Jones"},
+	)
+
+	err := s.Rows(&items, rows)
+	assert.ErrorIs(t, err, scan.ErrStructFieldMissing)
+	assert.Contains(t, err.Error(), `"last"`)
+	assert.Empty(t, items)
+	assert.Equal(t, 0, rows.ScanCallCount())
+}
+
+func TestScannerErrorOnUnfilledFields(t *testing.T) {
+	s := scan.New(scan.Config{ErrorOnUnfilledFields: true})
+
+	type person struct {
+		First string `db:"first"`
+		Last  string `db:"last"`
+		Age   int
+	}
+	var item person
+	rows := fakeRowsWithRecords(t, []string{"first", "extra"},
+		[]interface{}{"Brett", "Jones"},
+	)
+
+	err := s.Row(&item, rows)
+	assert.ErrorIs(t, err, scan.ErrStructFieldMissing)
+	assert.Contains(t, err.Error(), "Last")
+	assert.NotContains(t, err.Error(), "Age")
+	assert.NotContains(t, err.Error(), "extra")
+}
+
+func TestScannerAccountingPassesWhenAllColumnsAreMapped(t *testing.T) {
+	s := scan.New(scan.Config{ErrorOnUnmappedColumns: true, ErrorOnUnfilledFields: true})
+
+	var item struct {
+		First string `db:"first"`
+		Last  string
+	}
+	rows := fakeRowsWithRecords(t, []string{"first", "last"},
+		[]interface{}{"Brett", "Jones"},
+	)
+
+	require.NoError(t, s.Row(&item, rows))
+	assert.Equal(t, "Jones", item.Last)
+}
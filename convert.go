@@ -0,0 +1,192 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+// ErrConvertedType is returned when a converter registered with
+// RegisterConverter returns a value which cannot be assigned to its field.
+var ErrConvertedType = errors.New("converted value is not assignable")
+
+// ConvertFunc converts a value for a registered type. Converters registered
+// with RegisterConverter receive the value scanned from a column, which is
+// nil when the column is NULL, and return a value of the registered type.
+// Converters registered with RegisterValueConverter receive the value of a
+// field and return the value of its column.
+type ConvertFunc func(src interface{}) (interface{}, error)
+
+// globalConverters holds the converters used by the package-level functions.
+// Scanners start with a copy of them.
+var globalConverters = newConverters()
+
+// RegisterConverter registers fn to convert column values into fields, or
+// rows, of type t. The column is scanned into an interface{} first, so fn
+// receives the value returned by the driver, e.g. "Y" for a CHAR(1) column,
+// and returns a value which is assignable to t.
+//
+// A converter registered for T is used for *T fields as well. NULL leaves a
+// *T field nil without calling fn.
+//
+// Registered converters are used by the package-level functions and by the
+// Scanners created afterwards. Use Scanner.RegisterConverter to register a
+// converter for a single Scanner.
+func RegisterConverter(t reflect.Type, fn ConvertFunc) {
+	globalConverters.register(globalConverters.scan, t, fn)
+	resetCaches(columnsCache, valuesCache, plansCache)
+}
+
+// RegisterValueConverter registers fn to convert fields of type t into the
+// values returned by Values. It is the reverse of RegisterConverter.
+func RegisterValueConverter(t reflect.Type, fn ConvertFunc) {
+	globalConverters.register(globalConverters.value, t, fn)
+	resetCaches(columnsCache, valuesCache, plansCache)
+}
+
+// RegisterConverter registers fn to convert column values into fields, or
+// rows, of type t for s only. See RegisterConverter for details.
+func (s *Scanner) RegisterConverter(t reflect.Type, fn ConvertFunc) {
+	s.converters.register(s.converters.scan, t, fn)
+	resetCaches(s.columns, s.values, s.plans)
+}
+
+// RegisterValueConverter registers fn to convert fields of type t into the
+// values returned by Values for s only.
+func (s *Scanner) RegisterValueConverter(t reflect.Type, fn ConvertFunc) {
+	s.converters.register(s.converters.value, t, fn)
+	resetCaches(s.columns, s.values, s.plans)
+}
+
+// converters is a registry of ConvertFuncs by type.
+type converters struct {
+	mu    sync.RWMutex
+	scan  map[reflect.Type]ConvertFunc
+	value map[reflect.Type]ConvertFunc
+}
+
+func newConverters() *converters {
+	return &converters{
+		scan:  map[reflect.Type]ConvertFunc{},
+		value: map[reflect.Type]ConvertFunc{},
+	}
+}
+
+// clone returns a copy of c which can be changed independently.
+func (c *converters) clone() *converters {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	clone := newConverters()
+	for t, fn := range c.scan {
+		clone.scan[t] = fn
+	}
+	for t, fn := range c.value {
+		clone.value[t] = fn
+	}
+	return clone
+}
+
+func (c *converters) register(m map[reflect.Type]ConvertFunc, t reflect.Type, fn ConvertFunc) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	m[t] = fn
+}
+
+// lookup returns the converter of m for t, or for the type t points to in
+// which case deref is true.
+func (c *converters) lookup(m map[reflect.Type]ConvertFunc, t reflect.Type) (fn ConvertFunc, deref bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	if fn, ok := m[t]; ok {
+		return fn, false
+	}
+	if t.Kind() == reflect.Ptr {
+		return m[t.Elem()], true
+	}
+	return nil, false
+}
+
+// scanner returns the converter used to scan columns into values of type t,
+// falling back to the UnmarshalText and UnmarshalBinary methods of t. deref
+// is true when the converter is the one of the type t points to.
+func (c *converters) scanner(t reflect.Type) (fn ConvertFunc, deref bool) {
+	if fn, deref := c.lookup(c.scan, t); fn != nil {
+		return fn, deref
+	}
+	return unmarshalConverter(t)
+}
+
+// isLeaf reports whether values of type t are converted as a whole, in which
+// case they are not treated as nested structs.
+func (c *converters) isLeaf(t reflect.Type) bool {
+	if fn, _ := c.scanner(t); fn != nil {
+		return true
+	}
+	fn, _ := c.lookup(c.value, t)
+	return fn != nil || isTextMarshalerType(indirectType(t))
+}
+
+// valuer returns the function which returns the column value of fields of
+// type t using their value converter, falling back to their MarshalText
+// method.
+func (c *converters) valuer(t reflect.Type) func(reflect.Value) (interface{}, error) {
+	fn, deref := c.lookup(c.value, t)
+	switch {
+	case fn == nil && isTextMarshalerType(indirectType(t)):
+		return textValue
+	case fn == nil:
+		return interfaceValue
+	case deref:
+		return func(field reflect.Value) (interface{}, error) {
+			if field.IsNil() {
+				return nil, nil
+			}
+			return fn(field.Elem().Interface())
+		}
+	}
+	return func(field reflect.Value) (interface{}, error) {
+		return fn(field.Interface())
+	}
+}
+
+// interfaceValue returns the value of field as is.
+func interfaceValue(field reflect.Value) (interface{}, error) {
+	return field.Interface(), nil
+}
+
+// setConverted sets field to v, the result of a converter. Pointers are
+// allocated for values of the type they point to and a nil v sets the zero
+// value.
+func setConverted(field reflect.Value, v interface{}) error {
+	if v == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	rv := reflect.ValueOf(v)
+	switch t := field.Type(); {
+	case rv.Type().AssignableTo(t):
+		field.Set(rv)
+	case t.Kind() == reflect.Ptr && rv.Type().AssignableTo(t.Elem()):
+		p := reflect.New(t.Elem())
+		p.Elem().Set(rv)
+		field.Set(p)
+	default:
+		return fmt.Errorf("converter returned %T for %s: %w", v, t, ErrConvertedType)
+	}
+	return nil
+}
+
+// resetCaches empties caches which depend on the registered converters.
+func resetCaches(caches ...cache) {
+	for _, c := range caches {
+		c.Range(func(key, _ interface{}) bool {
+			c.Delete(key)
+			return true
+		})
+	}
+}
@@ -0,0 +1,210 @@
+package scan_test
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func yesNo(src interface{}) (interface{}, error) {
+	switch src {
+	case "Y":
+		return true, nil
+	case "N", nil:
+		return false, nil
+	default:
+		return nil, fmt.Errorf("invalid flag %v", src)
+	}
+}
+
+func unixTime(src interface{}) (interface{}, error) {
+	return time.Unix(src.(int64), 0).UTC(), nil
+}
+
+func commaList(src interface{}) (interface{}, error) {
+	return strings.Split(src.(string), ","), nil
+}
+
+func TestScannerConvertsFields(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterConverter(reflect.TypeOf(false), yesNo)
+	s.RegisterConverter(reflect.TypeOf(time.Time{}), unixTime)
+	s.RegisterConverter(reflect.TypeOf([]string{}), commaList)
+
+	type person struct {
+		Active  bool      `db:"active"`
+		Created time.Time `db:"created"`
+		Tags    []string  `db:"tags"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"active", "created", "tags"},
+		[]interface{}{"Y", int64(1700000000), "a,b"},
+		[]interface{}{nil, int64(0), "c"},
+	)
+
+	var persons []person
+	require.NoError(t, s.Rows(&persons, rows))
+	assert.Equal(t, []person{
+		{Active: true, Created: time.Unix(1700000000, 0).UTC(), Tags: []string{"a", "b"}},
+		{Active: false, Created: time.Unix(0, 0).UTC(), Tags: []string{"c"}},
+	}, persons)
+}
+
+func TestScannerConvertsPointerFields(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterConverter(reflect.TypeOf(false), yesNo)
+
+	var item struct {
+		Active *bool `db:"active"`
+		Admin  *bool `db:"admin"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"active", "admin"},
+		[]interface{}{"Y", nil},
+	)
+
+	require.NoError(t, s.Row(&item, rows))
+	require.NotNil(t, item.Active)
+	assert.True(t, *item.Active)
+	assert.Nil(t, item.Admin)
+}
+
+func TestScannerConvertsPrimitiveRows(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterConverter(reflect.TypeOf(false), yesNo)
+
+	rows := fakeRowsWithRecords(t, []string{"active"},
+		[]interface{}{"Y"},
+		[]interface{}{"N"},
+	)
+
+	var flags []bool
+	require.NoError(t, s.Rows(&flags, rows))
+	assert.Equal(t, []bool{true, false}, flags)
+}
+
+func TestScannerReturnsConverterErrors(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterConverter(reflect.TypeOf(false), yesNo)
+
+	var item struct {
+		Active bool `db:"active"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"active"},
+		[]interface{}{"X"},
+	)
+
+	err := s.Row(&item, rows)
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "active", se.Column)
+	assert.Equal(t, "Active", se.Field)
+	assert.EqualError(t, se.Err, "invalid flag X")
+}
+
+func TestScannerErrorsWhenConverterReturnsWrongType(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterConverter(reflect.TypeOf(false), func(interface{}) (interface{}, error) {
+		return "yes", nil
+	})
+
+	var item struct {
+		Active bool `db:"active"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"active"},
+		[]interface{}{"Y"},
+	)
+
+	err := s.Row(&item, rows)
+	assert.True(t, errors.Is(err, scan.ErrConvertedType))
+}
+
+func TestScannerConvertersAreScopedToTheScanner(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterConverter(reflect.TypeOf(false), yesNo)
+
+	var item struct {
+		Active bool `db:"active"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"active"},
+		[]interface{}{true},
+	)
+
+	require.NoError(t, scan.Row(&item, rows))
+	assert.True(t, item.Active)
+}
+
+type convertedPoint struct {
+	X, Y int
+}
+
+func TestRegisterConverterAppliesToPackageFunctions(t *testing.T) {
+	scan.RegisterConverter(reflect.TypeOf(convertedPoint{}), func(src interface{}) (interface{}, error) {
+		var p convertedPoint
+		_, err := fmt.Sscanf(src.(string), "(%d,%d)", &p.X, &p.Y)
+		return p, err
+	})
+	scan.RegisterValueConverter(reflect.TypeOf(convertedPoint{}), func(v interface{}) (interface{}, error) {
+		p := v.(convertedPoint)
+		return fmt.Sprintf("(%d,%d)", p.X, p.Y), nil
+	})
+
+	type place struct {
+		Name     string          `db:"name"`
+		Location convertedPoint  `db:"location"`
+		Previous *convertedPoint `db:"previous"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"name", "location", "previous"},
+		[]interface{}{"home", "(1,2)", nil},
+	)
+
+	var p place
+	require.NoError(t, scan.Row(&p, rows))
+	assert.Equal(t, place{Name: "home", Location: convertedPoint{1, 2}}, p)
+
+	cols, err := scan.Columns(&p)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"name", "location", "previous"}, cols)
+
+	vals, err := scan.Values(cols, &p)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"home", "(1,2)", nil}, vals)
+
+	p.Previous = &convertedPoint{3, 4}
+	vals, err = scan.Values([]string{"previous"}, &p)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"(3,4)"}, vals)
+
+	s := scan.New(scan.DefaultConfig())
+	vals, err = s.Values([]string{"location"}, &p)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"(1,2)"}, vals, "new Scanners start with the registered converters")
+}
+
+func TestScannerValueConverterErrors(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterValueConverter(reflect.TypeOf(false), func(interface{}) (interface{}, error) {
+		return nil, errors.New("broken")
+	})
+
+	item := struct {
+		Active bool `db:"active"`
+	}{}
+
+	_, err := s.Values([]string{"active"}, &item)
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "active", se.Column)
+	assert.EqualError(t, se.Err, "broken")
+}
@@ -0,0 +1,147 @@
+package scan_test
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"io"
+	"reflect"
+	"testing"
+)
+
+// resultSet is a canned result returned by the test driver. Types holds the
+// database type name of every column and is used to derive the scan type.
+type resultSet struct {
+	Cols  []string
+	Types []string
+	Rows  [][]driver.Value
+}
+
+// queryTestDB returns the *sql.Rows of a query against a database which
+// returns sets, one result set after the other.
+func queryTestDB(t testing.TB, sets ...resultSet) *sql.Rows {
+	t.Helper()
+
+	db := sql.OpenDB(testConnector{sets: sets})
+	t.Cleanup(func() { db.Close() })
+
+	rows, err := db.Query("SELECT")
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { rows.Close() })
+	return rows
+}
+
+type testConnector struct {
+	sets []resultSet
+}
+
+func (c testConnector) Connect(context.Context) (driver.Conn, error) {
+	return &testConn{sets: c.sets}, nil
+}
+
+func (c testConnector) Driver() driver.Driver {
+	return testDriver{}
+}
+
+type testDriver struct{}
+
+func (testDriver) Open(string) (driver.Conn, error) {
+	panic("use testConnector")
+}
+
+type testConn struct {
+	sets []resultSet
+}
+
+func (c *testConn) Prepare(string) (driver.Stmt, error) {
+	return &testStmt{sets: c.sets}, nil
+}
+
+func (c *testConn) Close() error {
+	return nil
+}
+
+func (c *testConn) Begin() (driver.Tx, error) {
+	return nil, driver.ErrSkip
+}
+
+type testStmt struct {
+	sets []resultSet
+}
+
+func (s *testStmt) Close() error {
+	return nil
+}
+
+func (s *testStmt) NumInput() int {
+	return -1
+}
+
+func (s *testStmt) Exec([]driver.Value) (driver.Result, error) {
+	return driver.ResultNoRows, nil
+}
+
+func (s *testStmt) Query([]driver.Value) (driver.Rows, error) {
+	return &testRows{set: s.sets[0], next: s.sets[1:]}, nil
+}
+
+type testRows struct {
+	set  resultSet
+	next []resultSet
+	pos  int
+}
+
+func (r *testRows) Columns() []string {
+	return r.set.Cols
+}
+
+func (r *testRows) Close() error {
+	return nil
+}
+
+func (r *testRows) Next(dest []driver.Value) error {
+	if r.pos >= len(r.set.Rows) {
+		return io.EOF
+	}
+	copy(dest, r.set.Rows[r.pos])
+	r.pos++
+	return nil
+}
+
+func (r *testRows) HasNextResultSet() bool {
+	return len(r.next) > 0
+}
+
+func (r *testRows) NextResultSet() error {
+	if len(r.next) == 0 {
+		return io.EOF
+	}
+	r.set, r.next, r.pos = r.next[0], r.next[1:], 0
+	return nil
+}
+
+func (r *testRows) ColumnTypeDatabaseTypeName(i int) string {
+	if i < len(r.set.Types) {
+		return r.set.Types[i]
+	}
+	return ""
+}
+
+func (r *testRows) ColumnTypeScanType(i int) reflect.Type {
+	switch r.ColumnTypeDatabaseTypeName(i) {
+	case "INTEGER", "BIGINT":
+		return reflect.TypeOf(int64(0))
+	case "DOUBLE":
+		return reflect.TypeOf(float64(0))
+	case "BOOLEAN":
+		return reflect.TypeOf(false)
+	case "TIMESTAMP":
+		return reflect.TypeOf(sql.NullTime{})
+	case "VARCHAR", "TEXT", "BLOB":
+		return reflect.TypeOf(sql.RawBytes{})
+	default:
+		return reflect.TypeOf(new(interface{})).Elem()
+	}
+}
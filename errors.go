@@ -0,0 +1,144 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// ScanError describes a failure to scan a column into its destination. It
+// wraps the underlying error, so it can be inspected with errors.Is and
+// errors.As.
+type ScanError struct {
+	// Row is the index of the row within the result, starting at 0. It is -1
+	// when the error is not related to a row, e.g. when returned by Values.
+	Row int
+
+	// Column is the name of the column, if known.
+	Column string
+
+	// Field is the dotted path of the destination struct field, e.g.
+	// "Company.Name", if the destination is a struct field.
+	Field string
+
+	// Type is the Go type of the destination. When no destination field was
+	// found it is the type of the struct.
+	Type reflect.Type
+
+	// Value is the value of the column as returned by the driver, if known.
+	Value interface{}
+
+	// Err is the underlying error.
+	Err error
+}
+
+func (e *ScanError) Error() string {
+	var b strings.Builder
+	b.WriteString("scan")
+	if e.Row >= 0 {
+		fmt.Fprintf(&b, " row %d", e.Row)
+	}
+	if e.Column != "" {
+		fmt.Fprintf(&b, " column %q", e.Column)
+	}
+	if e.Field != "" {
+		fmt.Fprintf(&b, " into field %s", e.Field)
+	}
+	if e.Type != nil {
+		fmt.Fprintf(&b, " (%s)", e.Type)
+	}
+	b.WriteString(": ")
+	b.WriteString(e.Err.Error())
+	return b.String()
+}
+
+func (e *ScanError) Unwrap() error {
+	return e.Err
+}
+
+// ErrTooManyScanErrors is wrapped by the ScanErrors of a lenient Scanner
+// which stopped scanning because more than MaxErrors rows failed.
+var ErrTooManyScanErrors = errors.New("too many scan errors")
+
+// ScanErrors is returned by a Scanner with the Lenient setting when rows
+// failed to scan. The rows which failed are skipped and the other rows are
+// scanned as usual. errors.Is and errors.As look into every error.
+type ScanErrors struct {
+	// Errors holds the error of every row which failed to scan, in order.
+	Errors []*ScanError
+
+	// Aborted is true when scanning stopped because more than MaxErrors rows
+	// failed, in which case the remaining rows were not scanned.
+	Aborted bool
+}
+
+func (e *ScanErrors) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d rows failed to scan", len(e.Errors))
+	if e.Aborted {
+		b.WriteString(" (aborted)")
+	}
+	if len(e.Errors) > 0 {
+		b.WriteString(", first: ")
+		b.WriteString(e.Errors[0].Error())
+	}
+	return b.String()
+}
+
+func (e *ScanErrors) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors)+1)
+	for _, se := range e.Errors {
+		errs = append(errs, se)
+	}
+	if e.Aborted {
+		errs = append(errs, ErrTooManyScanErrors)
+	}
+	return errs
+}
+
+// add records the error of a row which failed to scan and reports whether
+// scanning must stop because more than max rows failed. max is unlimited when
+// it is not positive.
+func (e *ScanErrors) add(se *ScanError, max int) bool {
+	e.Errors = append(e.Errors, se)
+	e.Aborted = max > 0 && len(e.Errors) > max
+	return e.Aborted
+}
+
+// failingColumn returns the index of the column whose destination in dests
+// fails to scan, or -1 if no single column fails. The columns are scanned
+// again one at a time, which requires that r allows Scan to be called more
+// than once for the same row, as *sql.Rows does.
+func failingColumn(r RowsScanner, dests []interface{}) int {
+	var discard interface{}
+	single := make([]interface{}, len(dests))
+
+	for i := range dests {
+		for j := range single {
+			single[j] = &discard
+		}
+		single[i] = dests[i]
+
+		if err := r.Scan(single...); err != nil {
+			return i
+		}
+	}
+	return -1
+}
+
+// rawValue returns the value of the column at index col of the current row of
+// r as returned by the driver, scanning the n columns of r again.
+func rawValue(r RowsScanner, n, col int) interface{} {
+	var discard, v interface{}
+	dests := make([]interface{}, n)
+	for i := range dests {
+		dests[i] = &discard
+	}
+	dests[col] = &v
+
+	if err := r.Scan(dests...); err != nil {
+		return nil
+	}
+	return v
+}
@@ -0,0 +1,90 @@
+package scan_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestScanErrorMessage(t *testing.T) {
+	err := &scan.ScanError{
+		Row:    2,
+		Column: "name",
+		Field:  "Company.Name",
+		Type:   reflect.TypeOf(""),
+		Err:    assert.AnError,
+	}
+	assert.Equal(t, `scan row 2 column "name" into field Company.Name (string): `+assert.AnError.Error(), err.Error())
+
+	err = &scan.ScanError{Row: -1, Err: assert.AnError}
+	assert.Equal(t, "scan: "+assert.AnError.Error(), err.Error())
+}
+
+func TestRowsReturnsScanErrorForFailingColumn(t *testing.T) {
+	rows := queryTestDB(t, resultSet{
+		Cols:  []string{"id", "name"},
+		Types: []string{"INTEGER", "VARCHAR"},
+		Rows: [][]driver.Value{
+			{int64(1), []byte("Brett")},
+			{int64(2), nil},
+		},
+	})
+
+	type person struct {
+		ID      int `db:"id"`
+		Company struct {
+			Name string `db:"name"`
+		}
+	}
+	var persons []person
+
+	err := scan.Rows(&persons, rows)
+
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se), "%v", err)
+	assert.Equal(t, 1, se.Row)
+	assert.Equal(t, "name", se.Column)
+	assert.Equal(t, "Company.Name", se.Field)
+	assert.Equal(t, reflect.TypeOf(""), se.Type)
+	assert.Contains(t, se.Err.Error(), "converting NULL to string is unsupported")
+}
+
+func TestRowsReturnsScanErrorForPrimitives(t *testing.T) {
+	rows := queryTestDB(t, resultSet{
+		Cols:  []string{"name"},
+		Types: []string{"VARCHAR"},
+		Rows: [][]driver.Value{
+			{nil},
+		},
+	})
+
+	var names []string
+	err := scan.Rows(&names, rows)
+
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se), "%v", err)
+	assert.Equal(t, 0, se.Row)
+	assert.Equal(t, "name", se.Column)
+	assert.Equal(t, "", se.Field)
+	assert.Equal(t, reflect.TypeOf(""), se.Type)
+}
+
+func TestValuesReturnsScanErrorForMissingFields(t *testing.T) {
+	type person struct {
+		Name string
+	}
+
+	_, err := scan.Values([]string{"Name", "age"}, &person{})
+
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se), "%v", err)
+	assert.True(t, errors.Is(err, scan.ErrStructFieldMissing))
+	assert.Equal(t, -1, se.Row)
+	assert.Equal(t, "age", se.Column)
+	assert.Equal(t, reflect.TypeOf(person{}), se.Type)
+}
@@ -81,7 +81,7 @@ func ExampleRow() {
 	// {"ID":1,"Name":"brett"}
 }
 
-func ExampleRowNested() {
+func ExampleRow_nested() {
 	db := exampleNestedDB()
 	defer db.Close()
 	rows, err := db.Query(`
@@ -137,7 +137,7 @@ func ExampleRowStrict() {
 	// {"ID":0,"Name":"brett"}
 }
 
-func ExampleRowPtr() {
+func ExampleRowStrict_pointer() {
 	db := exampleDB()
 	defer db.Close()
 	rows, err := db.Query("SELECT id,name FROM person where id = 3 LIMIT 1")
@@ -160,7 +160,7 @@ func ExampleRowPtr() {
 	// {"ID":0,"Name":null}
 }
 
-func ExampleRowPtrType() {
+func ExampleRowStrict_pointerType() {
 	db := exampleDB()
 	defer db.Close()
 	rows, err := db.Query("SELECT id,name FROM person where id = 3 LIMIT 1")
@@ -268,3 +268,44 @@ func ExampleRows_primitive() {
 	// Output:
 	// ["brett","fred"]
 }
+
+func ExampleAll() {
+	db := exampleDB()
+	defer db.Close()
+	rows, err := db.Query("SELECT id,name FROM person ORDER BY id ASC")
+	if err != nil {
+		panic(err)
+	}
+
+	type Person struct {
+		ID   int     `db:"id"`
+		Name *string `db:"name"`
+	}
+
+	persons, err := scan.All[Person](rows)
+	if err != nil {
+		panic(err)
+	}
+
+	json.NewEncoder(os.Stdout).Encode(persons)
+	// Output:
+	// [{"ID":1,"Name":"brett"},{"ID":2,"Name":"fred"},{"ID":3,"Name":null}]
+}
+
+func ExampleOne() {
+	db := exampleDB()
+	defer db.Close()
+	rows, err := db.Query("SELECT name FROM person WHERE id = 2")
+	if err != nil {
+		panic(err)
+	}
+
+	name, err := scan.One[string](rows)
+	if err != nil {
+		panic(err)
+	}
+
+	fmt.Printf("%q", name)
+	// Output:
+	// "fred"
+}
@@ -27,8 +27,9 @@ var record map[string]interface{}
 func fakeRowsWithRecords(t testing.TB, cols []string, rows ...[]interface{}) *FakeRowsScanner {
 	r := fakeRowsWithColumns(t, len(rows), cols...)
 	r.ScanStub = func(ps ...interface{}) error {
-		i := r.ScanCallCount() - 1
-		if i > len(rows) {
+		// rows may be scanned more than once, so use the current row
+		i := r.NextCallCount() - 1
+		if i >= len(rows) {
 			return nil
 		}
 		vals := rows[i]
@@ -0,0 +1,143 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrNotAFunc is returned by Func when the callback is not a func which
+	// returns nothing or an error.
+	ErrNotAFunc = errors.New("callback must be a func returning nothing or an error")
+
+	errorType = reflect.TypeOf((*error)(nil)).Elem()
+
+	// argsType is the type of the items that Func decodes rows into.
+	argsType = reflect.TypeOf([]reflect.Value(nil))
+)
+
+// Func scans every row of r into fresh values of the parameter types of fn
+// and calls fn with them, so that small queries don't need a struct:
+//
+//	err := scan.Func(rows, func(id int64, name string) error {
+//		// ...
+//		return nil
+//	})
+//
+// fn must have a parameter for every column, which are scanned by position,
+// and return nothing or an error. Parameter types with a converter are
+// converted and interface{} parameters get a Go type chosen from the column
+// type. Scanning stops at the first error returned by fn, which is returned
+// by Func. Rows which fail to scan are skipped when the Scanner is lenient.
+func Func(r RowsScanner, fn interface{}) error {
+	return std().Func(r, fn)
+}
+
+// Func scans every row of r into the parameters of fn and calls it. See Func
+// for details.
+func (s *Scanner) Func(r RowsScanner, fn interface{}) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
+	}
+
+	fnVal := reflect.ValueOf(fn)
+	fnType := reflect.TypeOf(fn)
+	if fnType == nil || fnType.Kind() != reflect.Func || fnVal.IsNil() || fnType.IsVariadic() ||
+		fnType.NumOut() > 1 || (fnType.NumOut() == 1 && fnType.Out(0) != errorType) {
+		return fmt.Errorf("%v: %w", fnType, ErrNotAFunc)
+	}
+
+	cols, err := r.Columns()
+	if err != nil {
+		return err
+	}
+	if n := fnType.NumIn(); len(cols) > n {
+		return fmt.Errorf("%d columns for %s: %w", len(cols), fnType, ErrTooManyColumns)
+	} else if len(cols) < n {
+		return fmt.Errorf("%d columns for %s: %w", len(cols), fnType, ErrNotEnoughColumns)
+	}
+
+	decode, err := s.argsDecoder(r, fnType, cols)
+	if err != nil {
+		return err
+	}
+
+	return s.decodeRows(r, argsType, decode, s.cfg.Lenient, func(item reflect.Value) error {
+		if out := fnVal.Call(item.Interface().([]reflect.Value)); len(out) == 1 && !out[0].IsNil() {
+			return out[0].Interface().(error)
+		}
+		return nil
+	})
+}
+
+// argsDecoder returns a decodeFunc which scans the current row of r into an
+// argsType item holding the arguments of a call to a func of fnType.
+func (s *Scanner) argsDecoder(r RowsScanner, fnType reflect.Type, cols []string) (decodeFunc, error) {
+	n := fnType.NumIn()
+	convs := make([]ConvertFunc, n)
+	derefs := make([]bool, n)
+	var vals []columnValue
+	for i := 0; i < n; i++ {
+		t := fnType.In(i)
+		convs[i], derefs[i] = s.converters.scanner(t)
+		if t == anyType && vals == nil {
+			var err error
+			if vals, err = columnValues(r, n); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	dests := make([]interface{}, n)
+	return func(item reflect.Value) error {
+		args := make([]reflect.Value, n)
+		for i := range args {
+			t := fnType.In(i)
+			args[i] = reflect.New(t).Elem()
+			switch {
+			case convs[i] != nil:
+				dests[i] = new(interface{})
+			case t == anyType:
+				dests[i] = vals[i].dest()
+			default:
+				dests[i] = args[i].Addr().Interface()
+			}
+		}
+
+		if err := r.Scan(dests...); err != nil {
+			se := &ScanError{Err: err}
+			if i := failingColumn(r, dests); i >= 0 {
+				se.Column = cols[i]
+				se.Type = fnType.In(i)
+				se.Value = rawValue(r, len(dests), i)
+			}
+			return se
+		}
+
+		for i, arg := range args {
+			var err error
+			var src interface{}
+			switch {
+			case convs[i] != nil:
+				src = *dests[i].(*interface{})
+				if src == nil && derefs[i] {
+					continue
+				}
+				var v interface{}
+				if v, err = convs[i](src); err == nil {
+					err = setConverted(arg, v)
+				}
+			case arg.Type() == anyType:
+				if v := vals[i].value(dests[i]); v != nil {
+					arg.Set(reflect.ValueOf(v))
+				}
+			}
+			if err != nil {
+				return &ScanError{Column: cols[i], Type: arg.Type(), Value: src, Err: err}
+			}
+		}
+		item.Set(reflect.ValueOf(args))
+		return nil
+	}, nil
+}
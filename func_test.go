@@ -0,0 +1,98 @@
+package scan_test
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFuncCallsFnForEveryRow(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name", "nick"},
+		[]interface{}{int64(1), "Brett", nil},
+		[]interface{}{int64(2), "Fred", "freddy"},
+	)
+
+	type call struct {
+		id   int64
+		name string
+		nick *string
+	}
+	var calls []call
+	require.NoError(t, scan.Func(rows, func(id int64, name string, nick *string) {
+		calls = append(calls, call{id, name, nick})
+	}))
+
+	nick := "freddy"
+	assert.Equal(t, []call{{1, "Brett", nil}, {2, "Fred", &nick}}, calls)
+	assert.Equal(t, 1, rows.CloseCallCount())
+}
+
+func TestFuncStopsAtCallbackError(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id"},
+		[]interface{}{int64(1)},
+		[]interface{}{int64(2)},
+	)
+
+	var ids []int64
+	err := scan.Func(rows, func(id int64) error {
+		ids = append(ids, id)
+		return assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+	assert.Equal(t, []int64{1}, ids)
+}
+
+func TestFuncScansInterfacesAndConverters(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterConverter(reflect.TypeOf(false), yesNo)
+
+	rows := fakeRowsWithRecords(t, []string{"active", "status", "raw"},
+		[]interface{}{"Y", "banned", int64(7)},
+	)
+
+	require.NoError(t, s.Func(rows, func(active bool, status textStatus, raw interface{}) {
+		assert.True(t, active)
+		assert.Equal(t, textStatusBanned, status)
+		assert.Equal(t, int64(7), raw)
+	}))
+}
+
+func TestFuncReturnsScanErrors(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "status"},
+		[]interface{}{int64(1), "active"},
+		[]interface{}{int64(2), "deleted"},
+	)
+
+	err := scan.Func(rows, func(int64, textStatus) {})
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, 1, se.Row)
+	assert.Equal(t, "status", se.Column)
+}
+
+func TestFuncErrorsOnColumnCount(t *testing.T) {
+	rows := fakeRowsWithColumns(t, 1, "id", "name")
+	err := scan.Func(rows, func(int64) {})
+	assert.True(t, errors.Is(err, scan.ErrTooManyColumns))
+
+	rows = fakeRowsWithColumns(t, 1, "id")
+	err = scan.Func(rows, func(int64, string) {})
+	assert.True(t, errors.Is(err, scan.ErrNotEnoughColumns))
+}
+
+func TestFuncErrorsForInvalidCallbacks(t *testing.T) {
+	for _, fn := range []interface{}{
+		nil,
+		42,
+		func(int64) int { return 0 },
+		func(...int64) {},
+		(func(int64))(nil),
+	} {
+		rows := fakeRowsWithColumns(t, 1, "id")
+		assert.True(t, errors.Is(scan.Func(rows, fn), scan.ErrNotAFunc), "%T", fn)
+	}
+}
@@ -0,0 +1,52 @@
+package scan
+
+import "errors"
+
+// All scans every row of r into a slice of T using the same mapping rules as
+// Rows.
+func All[T any](r RowsScanner) ([]T, error) {
+	return AllWith[T](std(), r)
+}
+
+// AllWith scans every row of r into a slice of T like All, using the
+// configuration, converters and variants of s. When s is lenient, the rows
+// which scanned are returned along with the *ScanErrors of the others.
+func AllWith[T any](s *Scanner, r RowsScanner) ([]T, error) {
+	var items []T
+	err := s.Rows(&items, r)
+	var report *ScanErrors
+	if err != nil && !errors.As(err, &report) {
+		return nil, err
+	}
+	return items, err
+}
+
+// First scans the first row of r into a T using the same mapping rules as
+// Row. Any remaining rows are ignored. sql.ErrNoRows is returned when r has
+// no rows.
+func First[T any](r RowsScanner) (T, error) {
+	return FirstWith[T](std(), r)
+}
+
+// FirstWith scans the first row of r into a T like First, using the
+// configuration, converters and variants of s.
+func FirstWith[T any](s *Scanner, r RowsScanner) (T, error) {
+	var item T
+	err := s.Row(&item, r)
+	return item, err
+}
+
+// One scans the only row of r into a T using the same mapping rules as
+// RowExactlyOne. sql.ErrNoRows is returned when r has no rows and
+// ErrTooManyRows is returned when it has more than one.
+func One[T any](r RowsScanner) (T, error) {
+	return OneWith[T](std(), r)
+}
+
+// OneWith scans the only row of r into a T like One, using the
+// configuration, converters and variants of s.
+func OneWith[T any](s *Scanner, r RowsScanner) (T, error) {
+	var item T
+	err := s.RowExactlyOne(&item, r)
+	return item, err
+}
@@ -0,0 +1,141 @@
+package scan_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type genericPerson struct {
+	ID   int64  `db:"id"`
+	Name string `db:"name"`
+}
+
+func TestAllScansStructs(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+		[]interface{}{int64(2), "Fred"},
+	)
+
+	persons, err := scan.All[genericPerson](rows)
+	require.NoError(t, err)
+	assert.Equal(t, []genericPerson{{1, "Brett"}, {2, "Fred"}}, persons)
+	assert.Equal(t, 1, rows.CloseCallCount())
+}
+
+func TestAllScansPrimitives(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+		[]interface{}{"Fred"},
+	)
+
+	names, err := scan.All[string](rows)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"Brett", "Fred"}, names)
+}
+
+func TestAllReturnsNilOnError(t *testing.T) {
+	rows := fakeRowsWithColumns(t, 1, "fname", "lname")
+
+	names, err := scan.All[string](rows)
+	assert.Nil(t, names)
+	assert.True(t, errors.Is(err, scan.ErrTooManyColumns))
+}
+
+func TestFirstReturnsFirstRow(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+		[]interface{}{int64(2), "Fred"},
+	)
+
+	person, err := scan.First[genericPerson](rows)
+	require.NoError(t, err)
+	assert.Equal(t, genericPerson{1, "Brett"}, person)
+}
+
+func TestFirstReturnsErrNoRows(t *testing.T) {
+	rows := fakeRowsWithColumns(t, 0, "id")
+
+	_, err := scan.First[genericPerson](rows)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestFirstErrorsForSlices(t *testing.T) {
+	rows := fakeRowsWithColumns(t, 1, "id")
+
+	_, err := scan.First[[]genericPerson](rows)
+	assert.Equal(t, scan.ErrSliceForRow, err)
+}
+
+func TestOneReturnsOnlyRow(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+	)
+
+	name, err := scan.One[string](rows)
+	require.NoError(t, err)
+	assert.Equal(t, "Brett", name)
+}
+
+func TestOneReturnsErrNoRows(t *testing.T) {
+	rows := fakeRowsWithColumns(t, 0, "name")
+
+	_, err := scan.One[string](rows)
+	assert.Equal(t, sql.ErrNoRows, err)
+}
+
+func TestOneReturnsErrTooManyRows(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+		[]interface{}{"Fred"},
+	)
+
+	name, err := scan.One[string](rows)
+	assert.Equal(t, scan.ErrTooManyRows, err)
+	assert.Equal(t, "", name)
+}
+
+func TestWithFunctionsUseTheScanner(t *testing.T) {
+	type person struct {
+		ID int64 `sql:"person_id"`
+	}
+	s := scan.New(scan.Config{TagName: "sql"})
+
+	persons, err := scan.AllWith[person](s, fakeRowsWithRecords(t, []string{"person_id"},
+		[]interface{}{int64(1)},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, []person{{1}}, persons)
+
+	p, err := scan.FirstWith[person](s, fakeRowsWithRecords(t, []string{"person_id"},
+		[]interface{}{int64(2)},
+		[]interface{}{int64(3)},
+	))
+	require.NoError(t, err)
+	assert.Equal(t, person{2}, p)
+
+	_, err = scan.OneWith[person](s, fakeRowsWithRecords(t, []string{"person_id"},
+		[]interface{}{int64(2)},
+		[]interface{}{int64(3)},
+	))
+	assert.Equal(t, scan.ErrTooManyRows, err)
+}
+
+func TestAllWithReturnsScannedRowsWhenLenient(t *testing.T) {
+	s := scan.New(scan.Config{Lenient: true})
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"id"},
+		Rows: [][]driver.Value{{int64(1)}, {"one"}, {int64(3)}},
+	})
+
+	ids, err := scan.AllWith[int64](s, rows)
+	assert.Equal(t, []int64{1, 3}, ids)
+	var report *scan.ScanErrors
+	require.True(t, errors.As(err, &report))
+	assert.Len(t, report.Errors, 1)
+}
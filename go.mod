@@ -1,6 +1,6 @@
 module github.com/blockloop/scan/v2
 
-go 1.17
+go 1.23
 
 require (
 	github.com/proullon/ramsql v0.0.1
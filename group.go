@@ -0,0 +1,217 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNoPrimaryKey is returned by RowsGrouped when the struct being scanned
+// has no field tagged with the pk option.
+var ErrNoPrimaryKey = errors.New("no primary key field")
+
+// RowsGrouped scans the rows of a one-to-many join into a slice of structs
+// (v), folding the rows which share a primary key into a single item. The
+// primary key is made of the fields tagged with the pk option, e.g.
+// `db:"id,pk"`.
+//
+// Slice fields of structs, such as Items []Item, are collections: the columns
+// of their fields are scanned like the ones of a nested struct and every
+// distinct child is appended to the collection of its parent. Children are
+// told apart by their own pk fields, which allows collections to be nested to
+// any depth. Children without a pk field are appended for every row. Rows
+// where all the columns of a child are NULL, as returned by a LEFT JOIN
+// without a match, add no child.
+func RowsGrouped(v interface{}, r RowsScanner) error {
+	return std().RowsGrouped(v, r)
+}
+
+// RowsGrouped scans the rows of a one-to-many join into a slice of structs
+// (v). See RowsGrouped for details.
+func (s *Scanner) RowsGrouped(v interface{}, r RowsScanner) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
+	}
+
+	vType := reflect.TypeOf(v)
+	if k := vType.Kind(); k != reflect.Ptr {
+		return fmt.Errorf("%q must be a pointer: %w", k.String(), ErrNotAPointer)
+	}
+	sliceType := vType.Elem()
+	if sliceType.Kind() != reflect.Slice || !isStructType(indirectType(sliceType.Elem())) {
+		return fmt.Errorf("%q must be a slice of structs: %w", sliceType.String(), ErrNotASlicePointer)
+	}
+	itemType := sliceType.Elem()
+	structType := indirectType(itemType)
+
+	node := s.groupNode(structType, nil)
+	if len(node.pk) == 0 {
+		return fmt.Errorf("%s: %w", structType, ErrNoPrimaryKey)
+	}
+
+	cols, err := r.Columns()
+	if err != nil {
+		return err
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+
+	decode, err := s.planDecoder(r, s.plan(structType, cols, s.cfg.Strict, true))
+	if err != nil {
+		return err
+	}
+
+	sliceVal := reflect.ValueOf(v).Elem()
+	root := &group{}
+	return s.decodeRows(r, itemType, func(item reflect.Value) error {
+		return decode(allocIndirect(item))
+	}, s.cfg.Lenient, func(item reflect.Value) error {
+		root.add(node, sliceVal, item)
+		return nil
+	})
+}
+
+// groupNode describes how the items of a struct type are grouped.
+type groupNode struct {
+	// pk holds the index paths of the primary key fields.
+	pk [][]int
+
+	// collections holds the collection fields of the struct.
+	collections []groupCollection
+}
+
+type groupCollection struct {
+	index []int
+	node  *groupNode
+}
+
+// groupNode returns the groupNode of typ. hops holds the pointer to struct
+// and collection types which were followed to reach typ.
+func (s *Scanner) groupNode(typ reflect.Type, hops []reflect.Type) *groupNode {
+	n := &groupNode{}
+	s.walkGroupNode(n, typ, nil, hops)
+	return n
+}
+
+// walkGroupNode adds the primary key and collection fields of typ to n. It
+// walks the nested structs the same way as taggedFields.
+func (s *Scanner) walkGroupNode(n *groupNode, typ reflect.Type, index []int, hops []reflect.Type) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		if s.isJSON(field) {
+			continue
+		}
+
+		if isCollection(field.Type, hops) {
+			if settablePath(typ, []int{i}) {
+				n.collections = append(n.collections, groupCollection{
+					index: fieldIndex,
+					node:  s.groupNode(nestedType(field.Type), appendHop(hops, field.Type)),
+				})
+			}
+			continue
+		}
+
+		if field.Type.Kind() == reflect.Struct || isPointerStruct(field.Type, hops) {
+			_, isPrefixed := s.prefix(field)
+			s.walkGroupNode(n, indirectType(field.Type), fieldIndex, appendHop(hops, field.Type))
+			if isPrefixed || field.Type.Kind() == reflect.Ptr {
+				continue
+			}
+		}
+
+		tag, ok := field.Tag.Lookup(s.cfg.TagName)
+		if !ok {
+			continue
+		}
+		if _, opts := parseTag(tag); opts.Contains("pk") {
+			n.pk = append(n.pk, fieldIndex)
+		}
+	}
+}
+
+// key returns the primary key of item, which is false when the struct has no
+// pk field.
+func (n *groupNode) key(item reflect.Value) (interface{}, bool) {
+	if len(n.pk) == 0 {
+		return nil, false
+	}
+
+	item = reflect.Indirect(item)
+	values := make([]interface{}, len(n.pk))
+	for i, index := range n.pk {
+		field, err := item.FieldByIndexErr(index)
+		if err != nil {
+			continue
+		}
+		if field = reflect.Indirect(field); field.IsValid() {
+			values[i] = field.Interface()
+		}
+	}
+
+	if v := values[0]; len(values) == 1 && (v == nil || reflect.TypeOf(v).Comparable()) {
+		return v, true
+	}
+	return fmt.Sprintf("%#v", values), true
+}
+
+// group indexes the items of a slice by their primary key.
+type group struct {
+	items map[interface{}]*groupEntry
+}
+
+type groupEntry struct {
+	// pos is the position of the item in its slice.
+	pos int
+
+	// children holds the group of every collection of the item.
+	children []*group
+}
+
+// add merges item into slice. An item with a new primary key is appended to
+// slice, otherwise the children of item are added to the collections of the
+// item which has the same primary key.
+func (g *group) add(n *groupNode, slice, item reflect.Value) {
+	// the children of the row are detached from item and added one by one
+	children := make([]reflect.Value, len(n.collections))
+	for i, c := range n.collections {
+		field, err := reflect.Indirect(item).FieldByIndexErr(c.index)
+		if err != nil {
+			continue
+		}
+		children[i] = reflect.ValueOf(field.Interface())
+		field.Set(reflect.Zero(field.Type()))
+	}
+
+	key, keyed := n.key(item)
+	entry := g.items[key]
+	if !keyed || entry == nil {
+		slice.Set(reflect.Append(slice, item))
+		entry = &groupEntry{
+			pos:      slice.Len() - 1,
+			children: make([]*group, len(n.collections)),
+		}
+		for i := range entry.children {
+			entry.children[i] = &group{}
+		}
+		if keyed {
+			if g.items == nil {
+				g.items = make(map[interface{}]*groupEntry)
+			}
+			g.items[key] = entry
+		}
+	}
+
+	parent := allocIndirect(slice.Index(entry.pos))
+	for i, c := range n.collections {
+		if !children[i].IsValid() {
+			continue
+		}
+		for j := 0; j < children[i].Len(); j++ {
+			entry.children[i].add(c.node, fieldByIndexAlloc(parent, c.index), children[i].Index(j))
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package scan_test
+
+import (
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type groupOption struct {
+	ID   int    `db:"id,pk"`
+	Name string `db:"name"`
+}
+
+type groupItem struct {
+	ID      int           `db:"id,pk"`
+	SKU     string        `db:"sku"`
+	Options []groupOption `db:"option_,prefix"`
+}
+
+type groupOrder struct {
+	ID       int          `db:"id,pk"`
+	Customer string       `db:"customer"`
+	Items    []*groupItem `db:"item_,prefix"`
+}
+
+func TestRowsGroupedFoldsChildrenIntoParents(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "customer", "item_id", "item_sku"},
+		[]interface{}{1, "alice", 10, "apple"},
+		[]interface{}{1, "alice", 11, "pear"},
+		[]interface{}{2, "bob", 12, "plum"},
+	)
+
+	var orders []groupOrder
+	require.NoError(t, scan.RowsGrouped(&orders, rows))
+	assert.Equal(t, []groupOrder{
+		{ID: 1, Customer: "alice", Items: []*groupItem{{ID: 10, SKU: "apple"}, {ID: 11, SKU: "pear"}}},
+		{ID: 2, Customer: "bob", Items: []*groupItem{{ID: 12, SKU: "plum"}}},
+	}, orders)
+}
+
+func TestRowsGroupedGroupsRowsWhichAreNotConsecutive(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "customer", "item_id", "item_sku"},
+		[]interface{}{1, "alice", 10, "apple"},
+		[]interface{}{2, "bob", 12, "plum"},
+		[]interface{}{1, "alice", 11, "pear"},
+	)
+
+	var orders []*groupOrder
+	require.NoError(t, scan.RowsGrouped(&orders, rows))
+	require.Len(t, orders, 2)
+	assert.Equal(t, []*groupItem{{ID: 10, SKU: "apple"}, {ID: 11, SKU: "pear"}}, orders[0].Items)
+	assert.Equal(t, []*groupItem{{ID: 12, SKU: "plum"}}, orders[1].Items)
+}
+
+func TestRowsGroupedNestsCollections(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "item_id", "item_option_id", "item_option_name"},
+		[]interface{}{1, 10, 100, "gift wrap"},
+		[]interface{}{1, 10, 101, "engraving"},
+		[]interface{}{1, 11, 100, "gift wrap"},
+	)
+
+	var orders []groupOrder
+	require.NoError(t, scan.RowsGrouped(&orders, rows))
+	assert.Equal(t, []groupOrder{{ID: 1, Items: []*groupItem{
+		{ID: 10, Options: []groupOption{{ID: 100, Name: "gift wrap"}, {ID: 101, Name: "engraving"}}},
+		{ID: 11, Options: []groupOption{{ID: 100, Name: "gift wrap"}}},
+	}}}, orders)
+}
+
+func TestRowsGroupedSkipsNullChildren(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "item_id", "item_sku"},
+		[]interface{}{1, nil, nil},
+		[]interface{}{2, 12, "plum"},
+	)
+
+	var orders []groupOrder
+	require.NoError(t, scan.RowsGrouped(&orders, rows))
+	assert.Equal(t, []groupOrder{
+		{ID: 1},
+		{ID: 2, Items: []*groupItem{{ID: 12, SKU: "plum"}}},
+	}, orders)
+}
+
+func TestRowsGroupedMapsDuplicateColumnsToChildren(t *testing.T) {
+	type item struct {
+		ID   int    `db:"id,pk"`
+		Name string `db:"name"`
+	}
+	type order struct {
+		ID    int    `db:"id,pk"`
+		Name  string `db:"name"`
+		Items []item
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"id", "name", "id", "name"},
+		[]interface{}{1, "first", 10, "apple"},
+		[]interface{}{1, "first", 11, "pear"},
+	)
+
+	var orders []order
+	require.NoError(t, scan.RowsGrouped(&orders, rows))
+	assert.Equal(t, []order{{ID: 1, Name: "first", Items: []item{{10, "apple"}, {11, "pear"}}}}, orders)
+}
+
+func TestRowsGroupedAppendsChildrenWithoutPrimaryKey(t *testing.T) {
+	type tag struct {
+		Name string `db:"name"`
+	}
+	type post struct {
+		ID   int   `db:"id,pk"`
+		Tags []tag `db:"tag_,prefix"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"id", "tag_name"},
+		[]interface{}{1, "go"},
+		[]interface{}{1, "go"},
+	)
+
+	var posts []post
+	require.NoError(t, scan.RowsGrouped(&posts, rows))
+	assert.Equal(t, []post{{ID: 1, Tags: []tag{{"go"}, {"go"}}}}, posts)
+}
+
+func TestRowsGroupedGroupsByCompositeKeys(t *testing.T) {
+	type line struct {
+		OrderID int    `db:"order_id,pk"`
+		Number  int    `db:"number,pk"`
+		SKU     string `db:"sku"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"order_id", "number", "sku"},
+		[]interface{}{1, 1, "apple"},
+		[]interface{}{1, 2, "pear"},
+		[]interface{}{1, 1, "apple"},
+	)
+
+	var lines []line
+	require.NoError(t, scan.RowsGrouped(&lines, rows))
+	assert.Equal(t, []line{{1, 1, "apple"}, {1, 2, "pear"}}, lines)
+}
+
+func TestRowsGroupedErrorsWithoutPrimaryKey(t *testing.T) {
+	type order struct {
+		ID int `db:"id"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"id"}, []interface{}{1})
+
+	var orders []order
+	assert.ErrorIs(t, scan.RowsGrouped(&orders, rows), scan.ErrNoPrimaryKey)
+}
+
+func TestRowsGroupedErrorsWhenNotASliceOfStructs(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id"}, []interface{}{1})
+
+	var ids []int
+	assert.ErrorIs(t, scan.RowsGrouped(&ids, rows), scan.ErrNotASlicePointer)
+}
+
+func TestRowsIgnoresCollections(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "item_id"},
+		[]interface{}{1, 10},
+	)
+
+	var orders []groupOrder
+	require.NoError(t, scan.Rows(&orders, rows))
+	assert.Equal(t, []groupOrder{{ID: 1}}, orders)
+}
@@ -13,6 +13,13 @@ type RowsScanner interface {
 	Next() bool
 }
 
+// ResultSetsScanner is a RowsScanner which can advance to the next result set
+// of a query returning several of them, such as *sql.Rows.
+type ResultSetsScanner interface {
+	RowsScanner
+	NextResultSet() bool
+}
+
 // cache is an interface for a sync.Map that is used for cache internally
 type cache interface {
 	Delete(key interface{})
@@ -0,0 +1,75 @@
+package scan
+
+import (
+	"errors"
+	"iter"
+	"reflect"
+)
+
+// errStopIteration is used internally to stop scanning when the consumer of
+// an iterator stops early.
+var errStopIteration = errors.New("stop iteration")
+
+// Each scans every row of r into a T and calls fn with it, without keeping
+// the rows in memory. It uses the same mapping rules as Rows. Scanning stops
+// at the first error returned by fn, which is returned by Each.
+func Each[T any](r RowsScanner, fn func(item T) error) error {
+	return EachWith(std(), r, fn)
+}
+
+// EachWith scans every row of r into a T and calls fn with it like Each,
+// using the configuration, converters and variants of s.
+func EachWith[T any](s *Scanner, r RowsScanner, fn func(item T) error) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
+	}
+
+	return s.each(r, typeOf[T](), s.cfg.Strict, s.cfg.Lenient, func(item reflect.Value) error {
+		return fn(*item.Addr().Interface().(*T))
+	})
+}
+
+// Iter returns an iterator that scans every row of r into a T using the same
+// mapping rules as Rows. A scanning error is yielded once as the last element
+// of the sequence. When AutoClose is true, r is closed when iteration ends,
+// including when the loop is exited early.
+//
+//	for person, err := range scan.Iter[Person](rows) {
+//		if err != nil {
+//			return err
+//		}
+//		// ...
+//	}
+func Iter[T any](r RowsScanner) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		// the package-level variables are read when iteration starts
+		IterWith[T](std(), r)(yield)
+	}
+}
+
+// IterWith returns an iterator like Iter which uses the configuration,
+// converters and variants of s. When s is lenient, the *ScanErrors of the
+// rows which failed is yielded once as the last element of the sequence.
+func IterWith[T any](s *Scanner, r RowsScanner) iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		if !s.cfg.DisableAutoClose {
+			defer s.closeRows(r)
+		}
+
+		err := s.each(r, typeOf[T](), s.cfg.Strict, s.cfg.Lenient, func(item reflect.Value) error {
+			if !yield(*item.Addr().Interface().(*T), nil) {
+				return errStopIteration
+			}
+			return nil
+		})
+		if err != nil && !errors.Is(err, errStopIteration) {
+			var zero T
+			yield(zero, err)
+		}
+	}
+}
+
+// typeOf returns the reflect.Type of T, including interface types.
+func typeOf[T any]() reflect.Type {
+	return reflect.TypeOf((*T)(nil)).Elem()
+}
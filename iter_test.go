@@ -0,0 +1,161 @@
+package scan_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEachCallsFnForEveryRow(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+		[]interface{}{int64(2), "Fred"},
+	)
+
+	var persons []genericPerson
+	err := scan.Each(rows, func(p genericPerson) error {
+		persons = append(persons, p)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []genericPerson{{1, "Brett"}, {2, "Fred"}}, persons)
+	assert.Equal(t, 1, rows.CloseCallCount())
+}
+
+func TestEachStopsWhenFnErrors(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+		[]interface{}{"Fred"},
+		[]interface{}{"Geoff"},
+	)
+
+	calls := 0
+	err := scan.Each(rows, func(name string) error {
+		calls++
+		return assert.AnError
+	})
+	assert.Equal(t, assert.AnError, err)
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, 1, rows.NextCallCount())
+	assert.Equal(t, 1, rows.CloseCallCount())
+}
+
+func TestEachReturnsScanErrors(t *testing.T) {
+	rows := fakeRowsWithColumns(t, 2, "name")
+	rows.ScanReturns(assert.AnError)
+
+	err := scan.Each(rows, func(name string) error {
+		t.Fatal("fn should not be called")
+		return nil
+	})
+	assert.ErrorIs(t, err, assert.AnError)
+}
+
+func TestIterYieldsEveryRow(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+		[]interface{}{int64(2), "Fred"},
+	)
+
+	var persons []genericPerson
+	for p, err := range scan.Iter[genericPerson](rows) {
+		require.NoError(t, err)
+		persons = append(persons, p)
+	}
+	assert.Equal(t, []genericPerson{{1, "Brett"}, {2, "Fred"}}, persons)
+	assert.Equal(t, 1, rows.CloseCallCount())
+}
+
+func TestIterClosesWhenLoopBreaks(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+		[]interface{}{"Fred"},
+		[]interface{}{"Geoff"},
+	)
+
+	var names []string
+	for name, err := range scan.Iter[string](rows) {
+		require.NoError(t, err)
+		names = append(names, name)
+		break
+	}
+	assert.Equal(t, []string{"Brett"}, names)
+	assert.Equal(t, 1, rows.NextCallCount())
+	assert.Equal(t, 1, rows.CloseCallCount())
+}
+
+func TestIterYieldsErrorLast(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+	)
+	rows.ErrReturns(assert.AnError)
+
+	var errs []error
+	var names []string
+	for name, err := range scan.Iter[string](rows) {
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		names = append(names, name)
+	}
+	assert.Equal(t, []string{"Brett"}, names)
+	require.Len(t, errs, 1)
+	assert.True(t, errors.Is(errs[0], assert.AnError))
+}
+
+func TestIterDoesNotCloseWithoutAutoClose(t *testing.T) {
+	scan.AutoClose = false
+	defer func() { scan.AutoClose = true }()
+
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+	)
+	for range scan.Iter[string](rows) {
+	}
+	assert.Equal(t, 0, rows.CloseCallCount())
+}
+
+func TestEachWithUsesTheScanner(t *testing.T) {
+	type person struct {
+		ID int64 `sql:"person_id"`
+	}
+	s := scan.New(scan.Config{TagName: "sql", DisableAutoClose: true})
+	rows := fakeRowsWithRecords(t, []string{"person_id"},
+		[]interface{}{int64(1)},
+		[]interface{}{int64(2)},
+	)
+
+	var ids []int64
+	require.NoError(t, scan.EachWith(s, rows, func(p person) error {
+		ids = append(ids, p.ID)
+		return nil
+	}))
+	assert.Equal(t, []int64{1, 2}, ids)
+	assert.Equal(t, 0, rows.CloseCallCount())
+}
+
+func TestIterWithYieldsLenientReport(t *testing.T) {
+	s := scan.New(scan.Config{Lenient: true})
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"id"},
+		Rows: [][]driver.Value{{int64(1)}, {"one"}, {int64(3)}},
+	})
+
+	var ids []int64
+	var report *scan.ScanErrors
+	for id, err := range scan.IterWith[int64](s, rows) {
+		if err != nil {
+			require.True(t, errors.As(err, &report))
+			continue
+		}
+		ids = append(ids, id)
+	}
+	assert.Equal(t, []int64{1, 3}, ids)
+	require.NotNil(t, report)
+	assert.Equal(t, 1, report.Errors[0].Row)
+}
@@ -0,0 +1,74 @@
+package scan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type jsonSettings struct {
+	Theme string `json:"theme"`
+	Beta  bool   `json:"beta"`
+}
+
+type jsonUser struct {
+	ID       int               `db:"id"`
+	Settings jsonSettings      `db:"settings,json"`
+	Prefs    *jsonSettings     `db:"prefs,json"`
+	Labels   map[string]string `db:"labels,json"`
+	Tags     []string          `db:"tags,json"`
+}
+
+func TestRowsDecodesJSONColumns(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "settings", "prefs", "labels", "tags"},
+		[]interface{}{1, []byte(`{"theme":"dark","beta":true}`), []byte(`{"theme":"light"}`), []byte(`{"a":"b"}`), []byte(`["x","y"]`)},
+		[]interface{}{2, nil, nil, nil, nil},
+	)
+
+	var users []jsonUser
+	require.NoError(t, scan.Rows(&users, rows))
+	assert.Equal(t, []jsonUser{
+		{
+			ID:       1,
+			Settings: jsonSettings{Theme: "dark", Beta: true},
+			Prefs:    &jsonSettings{Theme: "light"},
+			Labels:   map[string]string{"a": "b"},
+			Tags:     []string{"x", "y"},
+		},
+		{ID: 2},
+	}, users)
+}
+
+func TestRowReturnsJSONErrors(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"settings"},
+		[]interface{}{[]byte(`{`)},
+	)
+
+	var user jsonUser
+	err := scan.Row(&user, rows)
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "settings", se.Column)
+	assert.Equal(t, "Settings", se.Field)
+}
+
+func TestColumnsIncludesJSONFields(t *testing.T) {
+	cols, err := scan.Columns(&jsonUser{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "settings", "prefs", "labels", "tags"}, cols)
+}
+
+func TestValuesEncodesJSONFields(t *testing.T) {
+	user := jsonUser{
+		ID:       1,
+		Settings: jsonSettings{Theme: "dark"},
+		Tags:     []string{"x"},
+	}
+
+	vals, err := scan.Values([]string{"id", "settings", "prefs", "labels", "tags"}, &user)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1, `{"theme":"dark","beta":false}`, nil, nil, `["x"]`}, vals)
+}
@@ -0,0 +1,206 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+var (
+	// ErrNotAMapPointer is returned by RowsMap and RowsGroup when v is not a
+	// pointer to a map.
+	ErrNotAMapPointer = errors.New("not a map pointer")
+
+	// ErrDuplicateKey is returned by RowsMap when two rows have the same key.
+	ErrDuplicateKey = errors.New("duplicate key")
+
+	// ErrNullKey is returned by RowsMap and RowsGroup when the key of a row
+	// is NULL.
+	ErrNullKey = errors.New("key is NULL")
+
+	// ErrInvalidKey is returned by RowsMap and RowsGroup when rows cannot be
+	// keyed as requested, e.g. by a composite primary key or by a key which
+	// is not convertible to the key type of the map.
+	ErrInvalidKey = errors.New("invalid key")
+)
+
+// RowsMap scans sql rows into a map (v), such as a map[int64]User, using the
+// same mapping rules as Rows. Every row is keyed by the value of the column
+// named key, which must be scanned into a field of the struct, or by its only
+// field tagged with the pk option when key is empty. Values which are
+// map[string]interface{} are keyed by their key column. The type of the key
+// must be convertible to the key type of the map. A ScanError wrapping
+// ErrDuplicateKey is returned when two rows have the same key.
+func RowsMap(v interface{}, r RowsScanner, key string) error {
+	return std().RowsMap(v, r, key)
+}
+
+// RowsMap scans sql rows into a map (v) keyed by the column named key. See
+// RowsMap for details.
+func (s *Scanner) RowsMap(v interface{}, r RowsScanner, key string) error {
+	return s.rowsKeyed(v, r, key, false)
+}
+
+// RowsGroup scans sql rows into a map of slices (v), such as a
+// map[int64][]Order, appending every row to the slice of its key. The keys
+// are chosen as in RowsMap, e.g. RowsGroup(&orders, rows, "user_id") groups
+// orders by user.
+func RowsGroup(v interface{}, r RowsScanner, key string) error {
+	return std().RowsGroup(v, r, key)
+}
+
+// RowsGroup scans sql rows into a map of slices (v) grouped by the column
+// named key. See RowsGroup for details.
+func (s *Scanner) RowsGroup(v interface{}, r RowsScanner, key string) error {
+	return s.rowsKeyed(v, r, key, true)
+}
+
+func (s *Scanner) rowsKeyed(v interface{}, r RowsScanner, key string, grouped bool) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
+	}
+
+	vType := reflect.TypeOf(v)
+	if k := vType.Kind(); k != reflect.Ptr {
+		return fmt.Errorf("%q must be a pointer: %w", k.String(), ErrNotAPointer)
+	}
+	mapType := vType.Elem()
+	if mapType.Kind() != reflect.Map {
+		return fmt.Errorf("%q must be a map: %w", mapType.String(), ErrNotAMapPointer)
+	}
+	itemType := mapType.Elem()
+	if grouped {
+		if itemType.Kind() != reflect.Slice {
+			return fmt.Errorf("%q must be a map of slices: %w", mapType.String(), ErrNotAMapPointer)
+		}
+		itemType = itemType.Elem()
+	}
+
+	cols, err := r.Columns()
+	if err != nil {
+		return err
+	}
+	if len(cols) == 0 {
+		return nil
+	}
+
+	keyOf, err := s.keyFunc(itemType, cols, key)
+	if err != nil {
+		return err
+	}
+	decode, err := s.decoder(r, itemType, cols, s.cfg.Strict)
+	if err != nil {
+		return err
+	}
+
+	mapVal := reflect.ValueOf(v).Elem()
+	if mapVal.IsNil() {
+		mapVal.Set(reflect.MakeMap(mapType))
+	}
+	// row counts the decoded rows, including the ones skipped by a lenient
+	// Scanner, so that key errors report the index of their row
+	row := -1
+	return s.decodeRows(r, itemType, func(item reflect.Value) error {
+		row++
+		return decode(item)
+	}, s.cfg.Lenient, func(item reflect.Value) error {
+		k, err := keyOf(item)
+		if err == nil {
+			k, err = convertKey(k, mapType.Key())
+		}
+		if err != nil {
+			return &ScanError{Row: row, Column: key, Type: mapType.Key(), Err: err}
+		}
+
+		if grouped {
+			items := mapVal.MapIndex(k)
+			if !items.IsValid() {
+				items = reflect.Zero(mapType.Elem())
+			}
+			mapVal.SetMapIndex(k, reflect.Append(items, item))
+			return nil
+		}
+		if mapVal.MapIndex(k).IsValid() {
+			return &ScanError{Row: row, Column: key, Type: mapType.Key(), Err: fmt.Errorf("%v: %w", k, ErrDuplicateKey)}
+		}
+		mapVal.SetMapIndex(k, item)
+		return nil
+	})
+}
+
+// keyFunc returns a func which returns the key of the items of itemType
+// scanned from rows with cols, as described by RowsMap.
+func (s *Scanner) keyFunc(itemType reflect.Type, cols []string, key string) (func(item reflect.Value) (reflect.Value, error), error) {
+	base := indirectType(itemType)
+	if isMapType(base) {
+		if key == "" {
+			return nil, fmt.Errorf("%s: a key column is required for maps: %w", base, ErrInvalidKey)
+		}
+		return func(item reflect.Value) (reflect.Value, error) {
+			k := reflect.Indirect(item).MapIndex(reflect.ValueOf(key).Convert(base.Key()))
+			if !k.IsValid() || k.IsNil() {
+				return reflect.Value{}, ErrNullKey
+			}
+			return k.Elem(), nil
+		}, nil
+	}
+	if !isStructType(base) {
+		return nil, fmt.Errorf("%s: keyed rows must be structs or maps: %w", itemType, ErrInvalidKey)
+	}
+
+	var index []int
+	if key == "" {
+		pk := s.groupNode(base, nil).pk
+		if len(pk) == 0 {
+			return nil, fmt.Errorf("%s: %w", base, ErrNoPrimaryKey)
+		}
+		if len(pk) > 1 {
+			return nil, fmt.Errorf("%s: cannot key by a composite primary key: %w", base, ErrInvalidKey)
+		}
+		index = pk[0]
+	} else {
+		plan := s.plan(base, cols, s.cfg.Strict, false)
+		for i, col := range cols {
+			if col == key {
+				index = plan.fields[i]
+				break
+			}
+		}
+		if index == nil {
+			return nil, fmt.Errorf("key column %q is not scanned into a field of %s: %w", key, base, ErrStructFieldMissing)
+		}
+	}
+
+	return func(item reflect.Value) (reflect.Value, error) {
+		field, err := reflect.Indirect(item).FieldByIndexErr(index)
+		if err == nil {
+			field = reflect.Indirect(field)
+		}
+		if err != nil || !field.IsValid() {
+			return reflect.Value{}, ErrNullKey
+		}
+		return field, nil
+	}, nil
+}
+
+// convertKey converts k to the key type t of a map. Numbers are only
+// converted to numbers, so that an int key does not become a rune string.
+func convertKey(k reflect.Value, t reflect.Type) (reflect.Value, error) {
+	if k.Type().AssignableTo(t) {
+		return k, nil
+	}
+	if isNumberKind(k.Kind()) == isNumberKind(t.Kind()) && k.Type().ConvertibleTo(t) {
+		return k.Convert(t), nil
+	}
+	return reflect.Value{}, fmt.Errorf("key of type %s is not convertible to %s: %w", k.Type(), t, ErrInvalidKey)
+}
+
+func isNumberKind(k reflect.Kind) bool {
+	switch k {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,131 @@
+package scan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type keyedUser struct {
+	ID   int64  `db:"id,pk"`
+	Name string `db:"name"`
+}
+
+type keyedOrder struct {
+	ID     int    `db:"id"`
+	UserID *int64 `db:"user_id"`
+	Item   string `db:"item"`
+}
+
+func TestRowsMapKeysByColumn(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+		[]interface{}{int64(2), "Fred"},
+	)
+
+	var users map[int]keyedUser
+	require.NoError(t, scan.RowsMap(&users, rows, "id"))
+	assert.Equal(t, map[int]keyedUser{
+		1: {ID: 1, Name: "Brett"},
+		2: {ID: 2, Name: "Fred"},
+	}, users)
+}
+
+func TestRowsMapKeysByPrimaryKey(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+	)
+
+	var users map[int64]*keyedUser
+	require.NoError(t, scan.RowsMap(&users, rows, ""))
+	assert.Equal(t, map[int64]*keyedUser{1: {ID: 1, Name: "Brett"}}, users)
+}
+
+func TestRowsMapErrorsOnDuplicateKeys(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+		[]interface{}{int64(1), "Fred"},
+	)
+
+	var users map[int64]keyedUser
+	err := scan.RowsMap(&users, rows, "id")
+	assert.True(t, errors.Is(err, scan.ErrDuplicateKey))
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, 1, se.Row)
+	assert.Equal(t, "id", se.Column)
+}
+
+func TestRowsMapKeysMaps(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"code", "name"},
+		[]interface{}{"us", "United States"},
+	)
+
+	var countries map[string]map[string]interface{}
+	require.NoError(t, scan.RowsMap(&countries, rows, "code"))
+	assert.Equal(t, map[string]map[string]interface{}{
+		"us": {"code": "us", "name": "United States"},
+	}, countries)
+}
+
+func TestRowsMapErrors(t *testing.T) {
+	var users map[int64]keyedUser
+
+	err := scan.RowsMap(&users, fakeRowsWithColumns(t, 1, "id", "name"), "email")
+	assert.True(t, errors.Is(err, scan.ErrStructFieldMissing))
+
+	var orders map[int]keyedOrder
+	err = scan.RowsMap(&orders, fakeRowsWithColumns(t, 1, "id"), "")
+	assert.True(t, errors.Is(err, scan.ErrNoPrimaryKey))
+
+	err = scan.RowsMap(users, fakeRowsWithColumns(t, 1, "id"), "id")
+	assert.True(t, errors.Is(err, scan.ErrNotAPointer))
+
+	var list []keyedUser
+	err = scan.RowsMap(&list, fakeRowsWithColumns(t, 1, "id"), "id")
+	assert.True(t, errors.Is(err, scan.ErrNotAMapPointer))
+
+	var composite map[int]struct {
+		A int `db:"a,pk"`
+		B int `db:"b,pk"`
+	}
+	err = scan.RowsMap(&composite, fakeRowsWithColumns(t, 1, "a", "b"), "")
+	assert.True(t, errors.Is(err, scan.ErrInvalidKey))
+
+	var ms map[string]map[string]interface{}
+	err = scan.RowsMap(&ms, fakeRowsWithColumns(t, 1, "a"), "")
+	assert.True(t, errors.Is(err, scan.ErrInvalidKey))
+
+	var names map[string]keyedUser
+	err = scan.RowsMap(&names, fakeRowsWithRecords(t, []string{"id"}, []interface{}{int64(1)}), "id")
+	assert.True(t, errors.Is(err, scan.ErrInvalidKey))
+}
+
+func TestRowsGroupGroupsByColumn(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "user_id", "item"},
+		[]interface{}{1, int64(10), "apple"},
+		[]interface{}{2, int64(20), "pear"},
+		[]interface{}{3, int64(10), "plum"},
+	)
+
+	var orders map[int64][]keyedOrder
+	require.NoError(t, scan.RowsGroup(&orders, rows, "user_id"))
+	ten, twenty := int64(10), int64(20)
+	assert.Equal(t, map[int64][]keyedOrder{
+		10: {{ID: 1, UserID: &ten, Item: "apple"}, {ID: 3, UserID: &ten, Item: "plum"}},
+		20: {{ID: 2, UserID: &twenty, Item: "pear"}},
+	}, orders)
+}
+
+func TestRowsGroupErrorsOnNullKeys(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "user_id", "item"},
+		[]interface{}{1, nil, "apple"},
+	)
+
+	var orders map[int64][]keyedOrder
+	err := scan.RowsGroup(&orders, rows, "user_id")
+	assert.True(t, errors.Is(err, scan.ErrNullKey))
+}
@@ -0,0 +1,123 @@
+package scan_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type lenientPerson struct {
+	ID  int `db:"id"`
+	Age int `db:"age"`
+}
+
+func lenientRows(t *testing.T) *sql.Rows {
+	return queryTestDB(t, resultSet{
+		Cols: []string{"id", "age"},
+		Rows: [][]driver.Value{
+			{int64(1), int64(30)},
+			{int64(2), "thirty"},
+			{int64(3), int64(40)},
+			{int64(4), "n/a"},
+		},
+	})
+}
+
+func TestLenientRowsSkipsFailedRows(t *testing.T) {
+	s := scan.New(scan.Config{Lenient: true})
+
+	var persons []lenientPerson
+	err := s.Rows(&persons, lenientRows(t))
+	assert.Equal(t, []lenientPerson{{ID: 1, Age: 30}, {ID: 3, Age: 40}}, persons)
+
+	var report *scan.ScanErrors
+	require.True(t, errors.As(err, &report))
+	assert.False(t, report.Aborted)
+	require.Len(t, report.Errors, 2)
+	assert.Equal(t, 1, report.Errors[0].Row)
+	assert.Equal(t, "age", report.Errors[0].Column)
+	assert.Equal(t, "thirty", report.Errors[0].Value)
+	assert.Equal(t, 3, report.Errors[1].Row)
+	assert.Equal(t, "n/a", report.Errors[1].Value)
+
+	var se *scan.ScanError
+	assert.True(t, errors.As(err, &se))
+}
+
+func TestLenientRowsAbortsAfterMaxErrors(t *testing.T) {
+	s := scan.New(scan.Config{Lenient: true, MaxErrors: 1})
+
+	var persons []lenientPerson
+	err := s.Rows(&persons, lenientRows(t))
+	assert.True(t, errors.Is(err, scan.ErrTooManyScanErrors))
+	assert.Equal(t, []lenientPerson{{ID: 1, Age: 30}, {ID: 3, Age: 40}}, persons)
+
+	var report *scan.ScanErrors
+	require.True(t, errors.As(err, &report))
+	assert.True(t, report.Aborted)
+	assert.Len(t, report.Errors, 2)
+}
+
+func TestLenientRowsRecordsConverterErrors(t *testing.T) {
+	s := scan.New(scan.Config{Lenient: true})
+	s.RegisterConverter(reflect.TypeOf(false), yesNo)
+
+	rows := fakeRowsWithRecords(t, []string{"active"},
+		[]interface{}{"Y"},
+		[]interface{}{"X"},
+	)
+
+	var items []struct {
+		Active bool `db:"active"`
+	}
+	err := s.Rows(&items, rows)
+	require.Len(t, items, 1)
+
+	var report *scan.ScanErrors
+	require.True(t, errors.As(err, &report))
+	require.Len(t, report.Errors, 1)
+	assert.Equal(t, "X", report.Errors[0].Value)
+	assert.Equal(t, 1, report.Errors[0].Row)
+}
+
+func TestLenientFuncSkipsFailedRows(t *testing.T) {
+	s := scan.New(scan.Config{Lenient: true})
+
+	var ages []int
+	err := s.Func(lenientRows(t), func(id, age int) {
+		ages = append(ages, age)
+	})
+	assert.Equal(t, []int{30, 40}, ages)
+
+	var report *scan.ScanErrors
+	require.True(t, errors.As(err, &report))
+	assert.Len(t, report.Errors, 2)
+}
+
+func TestLenientDoesNotAffectRow(t *testing.T) {
+	s := scan.New(scan.Config{Lenient: true})
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"id", "age"},
+		Rows: [][]driver.Value{{int64(2), "thirty"}, {int64(3), int64(40)}},
+	})
+
+	var person lenientPerson
+	err := s.Row(&person, rows)
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "thirty", se.Value)
+}
+
+func TestScanErrorsMessage(t *testing.T) {
+	err := &scan.ScanErrors{
+		Errors:  []*scan.ScanError{{Row: 4, Column: "age", Err: errors.New("bad")}},
+		Aborted: true,
+	}
+	assert.EqualError(t, err, `1 rows failed to scan (aborted), first: scan row 4 column "age": bad`)
+}
@@ -0,0 +1,51 @@
+package scan
+
+import (
+	"reflect"
+)
+
+// isMapType reports whether t is a map which rows can be scanned into by
+// column name, such as map[string]interface{}.
+func isMapType(t reflect.Type) bool {
+	return t.Kind() == reflect.Map &&
+		t.Key().Kind() == reflect.String &&
+		t.Elem() == anyType
+}
+
+// mapDecoder scans each row into a map keyed by column name, choosing the Go
+// type of each value from the column types of r.
+func mapDecoder(r RowsScanner, cols []string) (decodeFunc, error) {
+	vals, err := columnValues(r, len(cols))
+	if err != nil {
+		return nil, err
+	}
+
+	dests := make([]interface{}, len(cols))
+	for i, v := range vals {
+		dests[i] = v.dest()
+	}
+
+	return func(item reflect.Value) error {
+		if err := r.Scan(dests...); err != nil {
+			se := &ScanError{Type: item.Type(), Err: err}
+			if i := failingColumn(r, dests); i >= 0 {
+				se.Column = cols[i]
+				se.Type = vals[i].typ
+				se.Value = rawValue(r, len(dests), i)
+			}
+			return se
+		}
+
+		m := reflect.MakeMapWithSize(item.Type(), len(cols))
+		for i, col := range cols {
+			key := reflect.ValueOf(col).Convert(item.Type().Key())
+			val := reflect.ValueOf(vals[i].value(dests[i]))
+			if !val.IsValid() {
+				val = reflect.Zero(anyType)
+			}
+			m.SetMapIndex(key, val)
+		}
+		item.Set(m)
+		return nil
+	}, nil
+}
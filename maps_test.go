@@ -0,0 +1,89 @@
+package scan_test
+
+import (
+	"database/sql/driver"
+	"testing"
+	"time"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowScansMap(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+	)
+
+	var m map[string]interface{}
+	require.NoError(t, scan.Row(&m, rows))
+	assert.Equal(t, map[string]interface{}{"id": int64(1), "name": "Brett"}, m)
+}
+
+func TestRowsScansMaps(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+		[]interface{}{int64(2), nil},
+	)
+
+	var ms []map[string]interface{}
+	require.NoError(t, scan.Rows(&ms, rows))
+	assert.Equal(t, []map[string]interface{}{
+		{"id": int64(1), "name": "Brett"},
+		{"id": int64(2), "name": nil},
+	}, ms)
+}
+
+func TestRowsScansMapsOfNamedTypes(t *testing.T) {
+	type record map[string]interface{}
+
+	rows := fakeRowsWithRecords(t, []string{"id"},
+		[]interface{}{int64(1)},
+	)
+
+	var ms []record
+	require.NoError(t, scan.Rows(&ms, rows))
+	assert.Equal(t, []record{{"id": int64(1)}}, ms)
+}
+
+func TestRowsScansMapsUsingColumnTypes(t *testing.T) {
+	now := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := queryTestDB(t, resultSet{
+		Cols:  []string{"id", "name", "score", "active", "created", "data", "other"},
+		Types: []string{"INTEGER", "VARCHAR", "DOUBLE", "BOOLEAN", "TIMESTAMP", "BLOB", ""},
+		Rows: [][]driver.Value{
+			{int64(1), []byte("Brett"), 1.5, true, now, []byte{1, 2}, []byte("raw")},
+			{nil, nil, nil, nil, nil, nil, nil},
+		},
+	})
+
+	var ms []map[string]interface{}
+	require.NoError(t, scan.Rows(&ms, rows))
+	require.Len(t, ms, 2)
+	assert.Equal(t, map[string]interface{}{
+		"id":      int64(1),
+		"name":    "Brett",
+		"score":   1.5,
+		"active":  true,
+		"created": now,
+		"data":    []byte{1, 2},
+		"other":   []byte("raw"),
+	}, ms[0])
+	assert.Equal(t, map[string]interface{}{
+		"id":      nil,
+		"name":    nil,
+		"score":   nil,
+		"active":  nil,
+		"created": nil,
+		"data":    nil,
+		"other":   nil,
+	}, ms[1])
+}
+
+func TestRowsMapReturnsColumnTypesError(t *testing.T) {
+	rows := fakeRowsWithColumns(t, 1, "id")
+	rows.ColumnTypesReturns(nil, assert.AnError)
+
+	var ms []map[string]interface{}
+	assert.Equal(t, assert.AnError, scan.Rows(&ms, rows))
+}
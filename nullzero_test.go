@@ -0,0 +1,105 @@
+package scan_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type nullZeroUser struct {
+	ID       int       `db:"id"`
+	Nickname string    `db:"nickname,nullzero"`
+	Visits   int       `db:"visits,nullzero"`
+	Seen     time.Time `db:"seen,nullzero"`
+}
+
+func TestRowsScansNullAsZeroWithNullZeroTag(t *testing.T) {
+	seen := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"id", "nickname", "visits", "seen"},
+		Rows: [][]driver.Value{
+			{int64(1), "bj", int64(3), seen},
+			{int64(2), nil, nil, nil},
+		},
+	})
+
+	var users []nullZeroUser
+	require.NoError(t, scan.Rows(&users, rows))
+	assert.Equal(t, []nullZeroUser{
+		{ID: 1, Nickname: "bj", Visits: 3, Seen: seen},
+		{ID: 2},
+	}, users)
+}
+
+func TestRowsFailsOnNullWithoutNullZero(t *testing.T) {
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"id", "name"},
+		Rows: [][]driver.Value{{int64(1), nil}},
+	})
+
+	var users []struct {
+		ID   int    `db:"id"`
+		Name string `db:"name"`
+	}
+	err := scan.Rows(&users, rows)
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "name", se.Column)
+}
+
+func TestScannerNullZeroAppliesToAllFields(t *testing.T) {
+	s := scan.New(scan.Config{NullZero: true})
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"id", "name", "nick"},
+		Rows: [][]driver.Value{{int64(1), nil, nil}},
+	})
+
+	var user struct {
+		ID   int     `db:"id"`
+		Name string  `db:"name"`
+		Nick *string `db:"nick"`
+	}
+	require.NoError(t, s.Row(&user, rows))
+	assert.Equal(t, 1, user.ID)
+	assert.Equal(t, "", user.Name)
+	assert.Nil(t, user.Nick)
+}
+
+func TestScannerNullZeroAppliesToPrimitives(t *testing.T) {
+	s := scan.New(scan.Config{NullZero: true})
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"name"},
+		Rows: [][]driver.Value{{"brett"}, {nil}},
+	})
+
+	var names []string
+	require.NoError(t, s.Rows(&names, rows))
+	assert.Equal(t, []string{"brett", ""}, names)
+}
+
+func TestValuesWritesNullForNullZeroFields(t *testing.T) {
+	user := nullZeroUser{ID: 0, Visits: 2}
+
+	vals, err := scan.Values([]string{"id", "nickname", "visits", "seen"}, &user)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{0, nil, 2, nil}, vals)
+
+}
+
+func TestScannerNullZeroDoesNotAffectValues(t *testing.T) {
+	s := scan.New(scan.Config{NullZero: true})
+	item := struct {
+		ID       int    `db:"id"`
+		Active   bool   `db:"active"`
+		Nickname string `db:"nickname,nullzero"`
+	}{}
+
+	vals, err := s.Values([]string{"id", "active", "nickname"}, &item)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{0, false, nil}, vals)
+}
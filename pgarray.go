@@ -0,0 +1,344 @@
+package scan
+
+import (
+	"bytes"
+	"encoding"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// errArraySyntax is wrapped by the errors returned for malformed array
+// literals.
+var errArraySyntax = errors.New("malformed array literal")
+
+// isArray reports whether the column of field holds a Postgres array, either
+// because field is tagged with the array option, e.g. `db:"tags,array"`, or
+// because the PostgresArrays setting is on and field is a slice without a
+// converter.
+func (s *Scanner) isArray(field reflect.StructField) bool {
+	if tag, ok := field.Tag.Lookup(s.cfg.TagName); ok {
+		_, opts := parseTag(tag)
+		if opts.Contains("array") {
+			return true
+		}
+		if opts.Contains("json") {
+			return false
+		}
+	}
+	if !s.cfg.PostgresArrays || !isArrayType(field.Type) {
+		return false
+	}
+	conv, _ := s.converters.scanner(field.Type)
+	return conv == nil
+}
+
+// isArrayType reports whether t, or the type t points to, is a slice which
+// can be scanned from a Postgres array.
+func isArrayType(t reflect.Type) bool {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Slice || t.Elem().Kind() == reflect.Uint8 {
+		return false
+	}
+	if reflect.PointerTo(t).Implements(sqlScannerType) || isStructType(indirectType(t.Elem())) {
+		return false
+	}
+	return true
+}
+
+// parseArray parses the Postgres array literal src, e.g. {1,2,3} or
+// {{"a",NULL},{"b","c"}}, into dest which is a slice or a pointer to a slice.
+func parseArray(src []byte, dest reflect.Value) error {
+	dest = allocIndirect(dest)
+
+	// skip the dimension decoration, e.g. [0:2]={1,2,3}
+	if len(src) > 0 && src[0] == '[' {
+		if i := bytes.IndexByte(src, '='); i >= 0 {
+			src = src[i+1:]
+		}
+	}
+
+	p := &arrayParser{src: src}
+	v, err := p.parse(dest.Type())
+	if err == nil && p.pos < len(p.src) {
+		err = p.errorf("unexpected %q after the array", p.src[p.pos:])
+	}
+	if err != nil {
+		return err
+	}
+	dest.Set(v)
+	return nil
+}
+
+type arrayParser struct {
+	src []byte
+	pos int
+}
+
+func (p *arrayParser) errorf(format string, args ...interface{}) error {
+	return fmt.Errorf("%w %q: %s", errArraySyntax, p.src, fmt.Sprintf(format, args...))
+}
+
+// parse parses an array into a new slice of type t.
+func (p *arrayParser) parse(t reflect.Type) (reflect.Value, error) {
+	if p.pos >= len(p.src) || p.src[p.pos] != '{' {
+		return reflect.Value{}, p.errorf("expected '{' at %d", p.pos)
+	}
+	p.pos++
+
+	slice := reflect.MakeSlice(t, 0, 0)
+	p.skipSpaces()
+	if p.pos < len(p.src) && p.src[p.pos] == '}' {
+		p.pos++
+		return slice, nil
+	}
+
+	for {
+		p.skipSpaces()
+
+		var elem reflect.Value
+		var err error
+		if p.pos < len(p.src) && p.src[p.pos] == '{' {
+			elemType := t.Elem()
+			if indirectType(elemType).Kind() != reflect.Slice {
+				return reflect.Value{}, fmt.Errorf("array has more dimensions than %s", t)
+			}
+			var sub reflect.Value
+			if sub, err = p.parse(indirectType(elemType)); err == nil {
+				elem = reflect.New(indirectType(elemType)).Elem()
+				elem.Set(sub)
+				elem = pointerTo(elem, elemType)
+			}
+		} else {
+			var text string
+			var isNull bool
+			if text, isNull, err = p.element(); err == nil {
+				elem, err = parseArrayElement(text, isNull, t.Elem())
+			}
+		}
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		slice = reflect.Append(slice, elem)
+
+		p.skipSpaces()
+		if p.pos >= len(p.src) {
+			return reflect.Value{}, p.errorf("unexpected end")
+		}
+		switch p.src[p.pos] {
+		case ',':
+			p.pos++
+		case '}':
+			p.pos++
+			return slice, nil
+		default:
+			return reflect.Value{}, p.errorf("unexpected %q at %d", p.src[p.pos], p.pos)
+		}
+	}
+}
+
+// element reads a quoted or unquoted element. isNull is true for an unquoted
+// NULL.
+func (p *arrayParser) element() (text string, isNull bool, err error) {
+	if p.pos < len(p.src) && p.src[p.pos] == '"' {
+		p.pos++
+		var b []byte
+		for p.pos < len(p.src) {
+			c := p.src[p.pos]
+			p.pos++
+			switch c {
+			case '\\':
+				if p.pos < len(p.src) {
+					b = append(b, p.src[p.pos])
+					p.pos++
+				}
+			case '"':
+				return string(b), false, nil
+			default:
+				b = append(b, c)
+			}
+		}
+		return "", false, p.errorf("unterminated quoted element")
+	}
+
+	start := p.pos
+	for p.pos < len(p.src) && p.src[p.pos] != ',' && p.src[p.pos] != '}' {
+		p.pos++
+	}
+	text = string(bytes.TrimSpace(p.src[start:p.pos]))
+	if text == "" {
+		return "", false, p.errorf("empty element at %d", start)
+	}
+	return text, strings.EqualFold(text, "NULL"), nil
+}
+
+func (p *arrayParser) skipSpaces() {
+	for p.pos < len(p.src) && (p.src[p.pos] == ' ' || p.src[p.pos] == '\t' || p.src[p.pos] == '\n') {
+		p.pos++
+	}
+}
+
+// parseArrayElement parses the text of an array element into a value of
+// type t.
+func parseArrayElement(text string, isNull bool, t reflect.Type) (reflect.Value, error) {
+	if isNull {
+		if k := t.Kind(); k != reflect.Ptr && k != reflect.Interface {
+			return reflect.Value{}, fmt.Errorf("cannot scan NULL array element into %s", t)
+		}
+		return reflect.Zero(t), nil
+	}
+
+	base := indirectType(t)
+	v := reflect.New(base).Elem()
+	if u, ok := v.Addr().Interface().(encoding.TextUnmarshaler); ok {
+		if err := u.UnmarshalText([]byte(text)); err != nil {
+			return reflect.Value{}, err
+		}
+		return pointerTo(v, t), nil
+	}
+
+	var err error
+	switch base.Kind() {
+	case reflect.String:
+		v.SetString(text)
+	case reflect.Interface:
+		v.Set(reflect.ValueOf(text))
+	case reflect.Bool:
+		var b bool
+		b, err = strconv.ParseBool(text)
+		v.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var n int64
+		n, err = strconv.ParseInt(text, 10, base.Bits())
+		v.SetInt(n)
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var n uint64
+		n, err = strconv.ParseUint(text, 10, base.Bits())
+		v.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		var f float64
+		f, err = strconv.ParseFloat(text, base.Bits())
+		v.SetFloat(f)
+	case reflect.Slice:
+		if base.Elem().Kind() != reflect.Uint8 || len(text) < 2 || text[:2] != `\x` {
+			return reflect.Value{}, fmt.Errorf("cannot scan array element %q into %s", text, t)
+		}
+		var b []byte
+		b, err = hex.DecodeString(text[2:])
+		v.SetBytes(b)
+	default:
+		return reflect.Value{}, fmt.Errorf("cannot scan array element into %s", t)
+	}
+	if err != nil {
+		return reflect.Value{}, fmt.Errorf("array element %q: %w", text, err)
+	}
+	return pointerTo(v, t), nil
+}
+
+// pointerTo returns v as a value of t, which is either the type of v or a
+// pointer to it.
+func pointerTo(v reflect.Value, t reflect.Type) reflect.Value {
+	if t.Kind() != reflect.Ptr {
+		return v
+	}
+	p := reflect.New(t.Elem())
+	p.Elem().Set(v)
+	return p
+}
+
+// formatArray returns the Postgres array literal of the slice v, or nil when
+// v is a nil slice or pointer.
+func formatArray(v reflect.Value) (interface{}, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return nil, nil
+		}
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Slice {
+		return nil, fmt.Errorf("cannot format %s as an array", v.Type())
+	}
+	if v.IsNil() {
+		return nil, nil
+	}
+
+	b, err := appendArray(nil, v)
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+func appendArray(b []byte, v reflect.Value) ([]byte, error) {
+	b = append(b, '{')
+	for i := 0; i < v.Len(); i++ {
+		if i > 0 {
+			b = append(b, ',')
+		}
+		var err error
+		if b, err = appendArrayElement(b, v.Index(i)); err != nil {
+			return nil, err
+		}
+	}
+	return append(b, '}'), nil
+}
+
+func appendArrayElement(b []byte, v reflect.Value) ([]byte, error) {
+	for v.Kind() == reflect.Ptr || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return append(b, "NULL"...), nil
+		}
+		v = v.Elem()
+	}
+
+	if m, ok := v.Interface().(encoding.TextMarshaler); ok {
+		text, err := m.MarshalText()
+		if err != nil {
+			return nil, err
+		}
+		return appendQuoted(b, string(text)), nil
+	}
+
+	switch v.Kind() {
+	case reflect.String:
+		return appendQuoted(b, v.String()), nil
+	case reflect.Bool:
+		if v.Bool() {
+			return append(b, 't'), nil
+		}
+		return append(b, 'f'), nil
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return strconv.AppendInt(b, v.Int(), 10), nil
+	case reflect.Uint, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return strconv.AppendUint(b, v.Uint(), 10), nil
+	case reflect.Float32, reflect.Float64:
+		return strconv.AppendFloat(b, v.Float(), 'g', -1, v.Type().Bits()), nil
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			return appendQuoted(b, `\x`+hex.EncodeToString(v.Bytes())), nil
+		}
+		if v.IsNil() {
+			return append(b, "NULL"...), nil
+		}
+		return appendArray(b, v)
+	default:
+		return nil, fmt.Errorf("cannot format %s as an array element", v.Type())
+	}
+}
+
+// appendQuoted appends s as a quoted array element.
+func appendQuoted(b []byte, s string) []byte {
+	b = append(b, '"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '"' || s[i] == '\\' {
+			b = append(b, '\\')
+		}
+		b = append(b, s[i])
+	}
+	return append(b, '"')
+}
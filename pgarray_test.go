@@ -0,0 +1,113 @@
+package scan
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func strPtr(s string) *string {
+	return &s
+}
+
+func TestParseArray(t *testing.T) {
+	tests := []struct {
+		src      string
+		expected interface{}
+	}{
+		{`{}`, []int64{}},
+		{`{1,2,3}`, []int64{1, 2, 3}},
+		{`{ 1 , -2 }`, []int{1, -2}},
+		{`{1.5,2}`, []float64{1.5, 2}},
+		{`{t,f,true}`, []bool{true, false, true}},
+		{`{a,"b c","d,e","f\"g","h\\i",""}`, []string{"a", "b c", "d,e", `f"g`, `h\i`, ""}},
+		{`{a,NULL,"NULL",null}`, []*string{strPtr("a"), nil, strPtr("NULL"), nil}},
+		{`{{1,2},{3,4}}`, [][]int64{{1, 2}, {3, 4}}},
+		{`{{"a"},{}}`, [][]string{{"a"}, {}}},
+		{`[0:1]={1,2}`, []int64{1, 2}},
+		{`{"\\x0102"}`, [][]byte{{1, 2}}},
+		{`{a,NULL}`, []interface{}{"a", nil}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			dest := reflect.New(reflect.TypeOf(tt.expected)).Elem()
+			require.NoError(t, parseArray([]byte(tt.src), dest))
+			assert.Equal(t, tt.expected, dest.Interface())
+		})
+	}
+}
+
+func TestParseArrayAllocatesPointers(t *testing.T) {
+	var dest *[]string
+	require.NoError(t, parseArray([]byte(`{a}`), reflect.ValueOf(&dest).Elem()))
+	assert.Equal(t, &[]string{"a"}, dest)
+}
+
+func TestParseArrayErrors(t *testing.T) {
+	tests := []struct {
+		src    string
+		dest   interface{}
+		syntax bool
+	}{
+		{`1,2`, []int{}, true},
+		{`{1,2`, []int{}, true},
+		{`{1,,2}`, []int{}, true},
+		{`{"a}`, []string{}, true},
+		{`{1}x`, []int{}, true},
+		{`{a}`, []int{}, false},
+		{`{NULL}`, []int{}, false},
+		{`{{1}}`, []int{}, false},
+		{`{300}`, []int8{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.src, func(t *testing.T) {
+			dest := reflect.New(reflect.TypeOf(tt.dest)).Elem()
+			err := parseArray([]byte(tt.src), dest)
+			require.Error(t, err)
+			assert.Equal(t, tt.syntax, errors.Is(err, errArraySyntax), err.Error())
+		})
+	}
+}
+
+func TestFormatArray(t *testing.T) {
+	tests := []struct {
+		value    interface{}
+		expected interface{}
+	}{
+		{[]int64(nil), nil},
+		{(*[]int64)(nil), nil},
+		{[]int64{}, `{}`},
+		{[]int64{1, 2, 3}, `{1,2,3}`},
+		{[]float64{1.5, 2}, `{1.5,2}`},
+		{[]bool{true, false}, `{t,f}`},
+		{[]string{"a", "b c", `f"g`, `h\i`}, `{"a","b c","f\"g","h\\i"}`},
+		{[]*string{strPtr("a"), nil}, `{"a",NULL}`},
+		{[][]int64{{1, 2}, {3, 4}}, `{{1,2},{3,4}}`},
+		{[][]byte{{1, 2}}, `{"\\x0102"}`},
+		{&[]int{1}, `{1}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(reflect.TypeOf(tt.value).String(), func(t *testing.T) {
+			v, err := formatArray(reflect.ValueOf(tt.value))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, v)
+		})
+	}
+}
+
+func TestFormatArrayRoundTrips(t *testing.T) {
+	value := [][]*string{{strPtr(`a "quoted", value`), nil}, {strPtr(`\`), strPtr("NULL")}}
+
+	literal, err := formatArray(reflect.ValueOf(value))
+	require.NoError(t, err)
+
+	var parsed [][]*string
+	require.NoError(t, parseArray([]byte(literal.(string)), reflect.ValueOf(&parsed).Elem()))
+	assert.Equal(t, value, parsed)
+}
@@ -0,0 +1,570 @@
+package scan
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+var plansCache cache = &sync.Map{}
+
+// planKey identifies a compiled scanPlan within the plan cache of a Scanner.
+type planKey struct {
+	Type        reflect.Type
+	Cols        string
+	Strict      bool
+	Collections bool
+}
+
+// scanPlan describes where each column of a result set is scanned into for a
+// given struct type. It is compiled once per column set and reused for every
+// row.
+type scanPlan struct {
+	typ    reflect.Type
+	cols   []string
+	strict bool
+
+	// fields holds the index path of the destination field of every column,
+	// or nil when the column has no destination.
+	fields [][]int
+
+	// nullable marks the columns whose field is reached through a pointer to
+	// a struct or a collection, or scans NULL as its zero value. They are
+	// scanned into a temporary value so that the pointers are only allocated
+	// and the field is only set when a value is not NULL.
+	nullable []bool
+
+	// json marks the columns whose field is tagged with the json option.
+	json []bool
+
+	// array marks the columns whose field holds a Postgres array.
+	array []bool
+
+	// ambiguous holds the indexes of the columns which have a duplicate name
+	// and could not be mapped to a distinct field.
+	ambiguous []int
+
+	// unfilled holds the index paths of the tagged fields which no column is
+	// scanned into.
+	unfilled [][]int
+
+	// rest holds the index path of the field tagged with the rest option,
+	// which collects the columns without a destination, or nil.
+	rest []int
+}
+
+// taggedField is a struct field which is tagged with a column name
+type taggedField struct {
+	column     string
+	occurrence int
+	index      []int
+}
+
+// plan returns the cached scanPlan for typ and cols, compiling it if needed.
+// When collections is true the fields of the structs held by slice fields
+// are mapped as well, which is used to group rows (see RowsGrouped).
+func (s *Scanner) plan(typ reflect.Type, cols []string, strict, collections bool) *scanPlan {
+	key := planKey{
+		Type:        typ,
+		Cols:        strings.Join(cols, "\x00"),
+		Strict:      strict,
+		Collections: collections,
+	}
+	if cached, ok := s.plans.Load(key); ok {
+		return cached.(*scanPlan)
+	}
+
+	p := s.compilePlan(typ, cols, strict, collections)
+	s.plans.Store(key, p)
+	return p
+}
+
+func (s *Scanner) compilePlan(typ reflect.Type, cols []string, strict, collections bool) *scanPlan {
+	var fields []taggedField
+	for _, f := range s.taggedFields(typ, nil, "", nil, collections, nil) {
+		if settablePath(typ, f.index) {
+			fields = append(fields, f)
+		}
+	}
+	tagged := occurrences(fields)
+
+	p := &scanPlan{
+		typ:      typ,
+		cols:     cols,
+		strict:   strict,
+		fields:   make([][]int, len(cols)),
+		nullable: make([]bool, len(cols)),
+		json:     make([]bool, len(cols)),
+		array:    make([]bool, len(cols)),
+		rest:     s.restField(typ),
+	}
+	seen := make(map[string]int, len(cols))
+	mapped := make(map[string]bool, len(cols))
+	filled := make(map[string]bool, len(cols))
+	for i, col := range cols {
+		occurrence := seen[col]
+		seen[col]++
+
+		var index []int
+		if slots, ok := tagged[col]; ok {
+			if occurrence < len(slots) {
+				index = slots[occurrence]
+			}
+		} else if !strict && occurrence == 0 {
+			if fieldIndex, found := s.fieldByName(typ, col, nil, collections); found && settablePath(typ, fieldIndex) {
+				index = fieldIndex
+			}
+		}
+
+		if index == nil {
+			if occurrence > 0 && mapped[col] {
+				p.ambiguous = append(p.ambiguous, i)
+			}
+			continue
+		}
+
+		p.fields[i] = index
+		p.nullable[i] = throughPointer(typ, index) || s.isNullZero(structField(typ, index))
+		p.json[i] = s.isJSON(structField(typ, index))
+		p.array[i] = s.isArray(structField(typ, index))
+		mapped[col] = true
+		filled[indexKey(index)] = true
+	}
+
+	for _, f := range fields {
+		if !filled[indexKey(f.index)] {
+			p.unfilled = append(p.unfilled, f.index)
+		}
+	}
+	return p
+}
+
+// taggedFields appends the tagged fields of typ, including the fields of
+// nested structs, to fields in declaration order. The column names of the
+// fields are prefixed with prefix. hops holds the pointer to struct and
+// collection types which were followed to reach typ. Collections are only
+// followed when collections is true.
+func (s *Scanner) taggedFields(typ reflect.Type, index []int, prefix string, hops []reflect.Type, collections bool, fields []taggedField) []taggedField {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		fieldIndex := append(index[:len(index):len(index)], i)
+
+		if s.isNested(field.Type, hops, collections) && !s.isJSON(field) {
+			childPrefix, isPrefixed := s.prefix(field)
+			fields = s.taggedFields(nestedType(field.Type), fieldIndex, prefix+childPrefix, appendHop(hops, field.Type), collections, fields)
+			if isPrefixed || field.Type.Kind() != reflect.Struct {
+				continue
+			}
+		}
+
+		tag, ok := field.Tag.Lookup(s.cfg.TagName)
+		if !ok {
+			continue
+		}
+		name, opts := parseTag(tag)
+		if name == "" || name == "-" || opts.Contains("prefix") || opts.Contains("rest") {
+			continue
+		}
+		fields = append(fields, taggedField{
+			column:     prefix + name,
+			occurrence: opts.occurrence(),
+			index:      fieldIndex,
+		})
+	}
+	return fields
+}
+
+// restField returns the index path of the map[string]interface{} field of
+// typ, or of its embedded structs, which is tagged with the rest option, e.g.
+// `db:",rest"`, or nil when there is none.
+func (s *Scanner) restField(typ reflect.Type) []int {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			if index := s.restField(field.Type); index != nil {
+				return append([]int{i}, index...)
+			}
+			continue
+		}
+
+		tag, ok := field.Tag.Lookup(s.cfg.TagName)
+		if !ok || !field.IsExported() || !isMapType(field.Type) {
+			continue
+		}
+		if _, opts := parseTag(tag); opts.Contains("rest") {
+			return []int{i}
+		}
+	}
+	return nil
+}
+
+// fieldByName returns the index path of the field which col maps to using
+// the ScannerMapper. Columns starting with the prefix of a nested struct are
+// looked up in that struct without the prefix. Prefixed collections are only
+// looked into when collections is true.
+func (s *Scanner) fieldByName(typ reflect.Type, col string, hops []reflect.Type, collections bool) ([]int, bool) {
+	if field, ok := typ.FieldByName(s.cfg.ScannerMapper(col)); ok {
+		return field.Index, true
+	}
+
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		prefix, ok := s.prefix(field)
+		if !ok || !strings.HasPrefix(col, prefix) {
+			continue
+		}
+		if field.Type.Kind() == reflect.Ptr && !isPointerStruct(field.Type, hops) {
+			continue
+		}
+		if field.Type.Kind() == reflect.Slice && !(collections && isCollection(field.Type, hops)) {
+			continue
+		}
+		if index, ok := s.fieldByName(nestedType(field.Type), strings.TrimPrefix(col, prefix), appendHop(hops, field.Type), collections); ok {
+			return append([]int{i}, index...), true
+		}
+	}
+	return nil, false
+}
+
+// isNested reports whether the fields of a struct field of type t are mapped
+// to columns individually.
+func (s *Scanner) isNested(t reflect.Type, hops []reflect.Type, collections bool) bool {
+	if s.converters.isLeaf(t) {
+		return false
+	}
+	return t.Kind() == reflect.Struct || isPointerStruct(t, hops) || (collections && isCollection(t, hops))
+}
+
+// isPointerStruct reports whether t is a pointer to a struct whose fields are
+// mapped to columns individually. Pointers to types in hops are excluded so
+// that self-referencing structs are only followed once.
+func isPointerStruct(t reflect.Type, hops []reflect.Type) bool {
+	if t.Kind() != reflect.Ptr || !isStructType(t.Elem()) || t.Implements(valuerType) {
+		return false
+	}
+	return !hasHop(hops, t)
+}
+
+// isCollection reports whether t is a slice of structs, or of pointers to
+// structs, whose fields are mapped to columns individually. Slice types in
+// hops are excluded so that self-referencing structs are only followed once.
+func isCollection(t reflect.Type, hops []reflect.Type) bool {
+	if t.Kind() != reflect.Slice || !isStructType(indirectType(t.Elem())) || reflect.PointerTo(t).Implements(sqlScannerType) {
+		return false
+	}
+	return !hasHop(hops, t)
+}
+
+func hasHop(hops []reflect.Type, t reflect.Type) bool {
+	for _, hop := range hops {
+		if hop == t {
+			return true
+		}
+	}
+	return false
+}
+
+// appendHop appends t to hops when it is a pointer or a slice.
+func appendHop(hops []reflect.Type, t reflect.Type) []reflect.Type {
+	if k := t.Kind(); k != reflect.Ptr && k != reflect.Slice {
+		return hops
+	}
+	return append(hops[:len(hops):len(hops)], t)
+}
+
+// nestedType returns the struct type whose fields are reached through a
+// field of type t, following pointers and the element type of a collection.
+func nestedType(t reflect.Type) reflect.Type {
+	t = indirectType(t)
+	if t.Kind() == reflect.Slice {
+		t = indirectType(t.Elem())
+	}
+	return t
+}
+
+// settablePath reports whether the field of typ at index can be set through
+// reflection once the pointers to structs along the path are allocated.
+func settablePath(typ reflect.Type, index []int) bool {
+	for _, x := range index {
+		typ = nestedType(typ)
+		if typ.Kind() != reflect.Struct {
+			return false
+		}
+		field := typ.Field(x)
+		if !field.IsExported() && !(field.Anonymous && field.Type.Kind() == reflect.Struct) {
+			return false
+		}
+		typ = field.Type
+	}
+	return true
+}
+
+// throughPointer reports whether the path to the field of typ at index
+// follows a pointer or a collection.
+func throughPointer(typ reflect.Type, index []int) bool {
+	for _, x := range index[:len(index)-1] {
+		typ = nestedType(typ).Field(x).Type
+		if k := typ.Kind(); k == reflect.Ptr || k == reflect.Slice {
+			return true
+		}
+	}
+	return false
+}
+
+// structField returns the field of typ at index.
+func structField(typ reflect.Type, index []int) reflect.StructField {
+	var field reflect.StructField
+	for _, x := range index {
+		field = nestedType(typ).Field(x)
+		typ = field.Type
+	}
+	return field
+}
+
+// fieldType returns the type of the field of typ at index.
+func fieldType(typ reflect.Type, index []int) reflect.Type {
+	return structField(typ, index).Type
+}
+
+// fieldByIndexAlloc returns the field of v at index, allocating the nil
+// pointers to structs along the path. Collections along the path are given a
+// single element.
+func fieldByIndexAlloc(v reflect.Value, index []int) reflect.Value {
+	for i, x := range index {
+		if i > 0 {
+			v = allocIndirect(v)
+			if v.Kind() == reflect.Slice {
+				if v.Len() == 0 {
+					v.Set(reflect.MakeSlice(v.Type(), 1, 1))
+				}
+				v = allocIndirect(v.Index(0))
+			}
+		}
+		v = v.Field(x)
+	}
+	return v
+}
+
+// check returns an error wrapping ErrStructFieldMissing when the plan has
+// columns without a destination, or tagged fields without a column when
+// unfilled is true. Duplicate columns which cannot be mapped to distinct
+// fields are reported in strict mode as well.
+func (p *scanPlan) check(unmapped, unfilled bool) error {
+	var msgs []string
+
+	isAmbiguous := make(map[int]bool, len(p.ambiguous))
+	if (unmapped || p.strict) && len(p.ambiguous) > 0 {
+		cols := make([]string, len(p.ambiguous))
+		for i, col := range p.ambiguous {
+			cols[i] = strconv.Quote(p.cols[col])
+			isAmbiguous[col] = true
+		}
+		msgs = append(msgs, fmt.Sprintf("no distinct field for duplicate columns %s", strings.Join(cols, ", ")))
+	}
+
+	if unmapped && p.rest == nil {
+		var missing []string
+		for i, index := range p.fields {
+			if index == nil && !isAmbiguous[i] {
+				missing = append(missing, strconv.Quote(p.cols[i]))
+			}
+		}
+		if len(missing) > 0 {
+			msgs = append(msgs, fmt.Sprintf("no field for columns %s", strings.Join(missing, ", ")))
+		}
+	}
+
+	if unfilled && len(p.unfilled) > 0 {
+		missing := make([]string, len(p.unfilled))
+		for i, index := range p.unfilled {
+			missing[i] = fieldPath(p.typ, index)
+		}
+		msgs = append(msgs, fmt.Sprintf("no column for fields %s", strings.Join(missing, ", ")))
+	}
+
+	if len(msgs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%s: %s: %w", p.typ, strings.Join(msgs, "; "), ErrStructFieldMissing)
+}
+
+// rowDests holds the scan destinations of a plan. They are reused for every
+// row.
+type rowDests struct {
+	plan    *scanPlan
+	dests   []interface{}
+	temps   []reflect.Value
+	convs   []ConvertFunc
+	derefs  []bool
+	rest    []columnValue
+	discard interface{}
+}
+
+// newDests returns the destinations of the plan. Columns whose field type
+// has a converter in c are scanned into an interface{} and converted. When
+// the plan has a rest field, the columns without a destination are scanned
+// as described by rest, which holds the columnValue of every column.
+func (p *scanPlan) newDests(c *converters, rest []columnValue) *rowDests {
+	d := &rowDests{
+		plan:   p,
+		dests:  make([]interface{}, len(p.fields)),
+		temps:  make([]reflect.Value, len(p.fields)),
+		convs:  make([]ConvertFunc, len(p.fields)),
+		derefs: make([]bool, len(p.fields)),
+	}
+	if p.rest != nil {
+		d.rest = make([]columnValue, len(p.fields))
+	}
+	isAmbiguous := make(map[int]bool, len(p.ambiguous))
+	for _, i := range p.ambiguous {
+		isAmbiguous[i] = true
+	}
+
+	for i, index := range p.fields {
+		if index == nil && p.rest != nil && !isAmbiguous[i] {
+			d.rest[i] = rest[i]
+			d.dests[i] = rest[i].dest()
+			continue
+		}
+		if index == nil {
+			// have to add if we found a column because Scan() requires
+			// len(cols) arguments or it will error. This way we can scan to
+			// a useless pointer
+			d.dests[i] = &d.discard
+			continue
+		}
+
+		typ := fieldType(p.typ, index)
+		if p.json[i] || p.array[i] {
+			// NULL leaves the raw JSON or array nil
+			d.temps[i] = reflect.New(bytesType)
+			d.dests[i] = d.temps[i].Interface()
+		} else if conv, deref := c.scanner(typ); conv != nil {
+			d.convs[i], d.derefs[i] = conv, deref
+			d.temps[i] = reflect.New(anyType)
+			d.dests[i] = d.temps[i].Interface()
+		} else if p.nullable[i] {
+			// a pointer to a pointer is set to nil when the column is NULL
+			d.temps[i] = reflect.New(reflect.PointerTo(typ))
+			d.dests[i] = d.temps[i].Interface()
+		}
+	}
+	return d
+}
+
+// prepare points the destinations of the fields at item and returns them.
+func (d *rowDests) prepare(item reflect.Value) []interface{} {
+	for i, index := range d.plan.fields {
+		if index != nil && !d.temps[i].IsValid() {
+			d.dests[i] = item.FieldByIndex(index).Addr().Interface()
+		}
+	}
+	return d.dests
+}
+
+// finish copies the values which were scanned into temporary values into
+// item, allocating pointers to structs for non-NULL values only. JSON and
+// array columns are decoded and the values of the fields which have a
+// converter are converted. The columns without a destination are stored in
+// the rest field.
+func (d *rowDests) finish(item reflect.Value) error {
+	if d.rest != nil {
+		field := fieldByIndexAlloc(item, d.plan.rest)
+		m := reflect.MakeMap(field.Type())
+		for i, v := range d.rest {
+			if v.typ == nil {
+				continue
+			}
+			val := reflect.ValueOf(v.value(d.dests[i]))
+			if !val.IsValid() {
+				val = reflect.Zero(anyType)
+			}
+			m.SetMapIndex(reflect.ValueOf(d.plan.cols[i]).Convert(field.Type().Key()), val)
+		}
+		field.Set(m)
+	}
+
+	for i, tmp := range d.temps {
+		if !tmp.IsValid() {
+			continue
+		}
+
+		isNull := tmp.Elem().IsNil()
+		if d.plan.json[i] || d.plan.array[i] {
+			if isNull {
+				continue
+			}
+			field := fieldByIndexAlloc(item, d.plan.fields[i])
+			var err error
+			if d.plan.json[i] {
+				err = json.Unmarshal(tmp.Elem().Bytes(), field.Addr().Interface())
+			} else {
+				err = parseArray(tmp.Elem().Bytes(), field)
+			}
+			if err != nil {
+				se := d.plan.scanError(i, err)
+				se.Value = tmp.Elem().Interface()
+				return se
+			}
+			continue
+		}
+
+		if d.convs[i] == nil {
+			if !isNull {
+				fieldByIndexAlloc(item, d.plan.fields[i]).Set(tmp.Elem().Elem())
+			}
+			continue
+		}
+
+		// NULL leaves pointers nil, other fields are converted from nil
+		if isNull && (d.plan.nullable[i] || d.derefs[i]) {
+			continue
+		}
+		src := tmp.Elem().Interface()
+		v, err := d.convs[i](src)
+		if err == nil {
+			err = setConverted(fieldByIndexAlloc(item, d.plan.fields[i]), v)
+		}
+		if err != nil {
+			se := d.plan.scanError(i, err)
+			se.Value = src
+			return se
+		}
+	}
+	return nil
+}
+
+// scanError returns a *ScanError for a failure to scan the column at index
+// col, which is -1 when the column is not known.
+func (p *scanPlan) scanError(col int, err error) *ScanError {
+	if col < 0 {
+		return &ScanError{Type: p.typ, Err: err}
+	}
+
+	se := &ScanError{Column: p.cols[col], Type: anyType, Err: err}
+	if index := p.fields[col]; index != nil {
+		se.Field = fieldPath(p.typ, index)
+		se.Type = fieldType(p.typ, index)
+	}
+	return se
+}
+
+// fieldPath returns the dotted path of the field of typ at index, e.g.
+// "Company.Name".
+func fieldPath(typ reflect.Type, index []int) string {
+	names := make([]string, len(index))
+	for i, x := range index {
+		typ = nestedType(typ)
+		field := typ.Field(x)
+		names[i] = field.Name
+		typ = field.Type
+	}
+	return strings.Join(names, ".")
+}
+
+func indexKey(index []int) string {
+	return fmt.Sprint(index)
+}
@@ -0,0 +1,154 @@
+package scan
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPlanMapsTagsAndFieldNames(t *testing.T) {
+	type person struct {
+		ID      int `db:"id"`
+		Name    string
+		private string `db:"private"`
+		Address struct {
+			City string `db:"address.city"`
+		}
+	}
+
+	s := New(Config{})
+	p := s.plan(reflect.TypeOf(person{}), []string{"id", "name", "private", "address.city", "missing"}, false, false)
+
+	assert.Equal(t, [][]int{{0}, {1}, nil, {3, 0}, nil}, p.fields)
+}
+
+func TestPlanStrictIgnoresFieldNames(t *testing.T) {
+	type person struct {
+		ID   int `db:"id"`
+		Name string
+	}
+
+	s := New(Config{})
+	p := s.plan(reflect.TypeOf(person{}), []string{"id", "name"}, true, false)
+
+	assert.Equal(t, [][]int{{0}, nil}, p.fields)
+}
+
+func TestPlanIsCachedPerColumnSet(t *testing.T) {
+	type person struct {
+		ID   int `db:"id"`
+		Name string
+	}
+
+	s := New(Config{})
+	typ := reflect.TypeOf(person{})
+
+	first := s.plan(typ, []string{"id", "name"}, false, false)
+	assert.Same(t, first, s.plan(typ, []string{"id", "name"}, false, false))
+	assert.NotSame(t, first, s.plan(typ, []string{"name", "id"}, false, false))
+	assert.NotSame(t, first, s.plan(typ, []string{"id", "name"}, true, false))
+
+	size := 0
+	s.plans.Range(func(key interface{}, value interface{}) bool {
+		size++
+		return true
+	})
+	assert.Equal(t, 3, size)
+}
+
+func TestPlanIsRecompiledWhenMapperChanges(t *testing.T) {
+	type person struct {
+		FIRST string
+	}
+
+	defer func(m func(string) string) { ScannerMapper = m }(ScannerMapper)
+
+	typ := reflect.TypeOf(person{})
+	cols := []string{"first"}
+	assert.Equal(t, [][]int{nil}, std().plan(typ, cols, false, false).fields)
+
+	ScannerMapper = strings.ToUpper
+	assert.Equal(t, [][]int{{0}}, std().plan(typ, cols, false, false).fields)
+}
+
+func TestPlanIsRecompiledWhenMapperClosureChanges(t *testing.T) {
+	type person struct {
+		XName string
+		YName string
+	}
+
+	defer func(m func(string) string) { ScannerMapper = m }(ScannerMapper)
+
+	// closures made from the same func literal share their code pointer
+	mk := func(prefix string) func(string) string {
+		return func(name string) string { return prefix + titleCase(name) }
+	}
+
+	typ := reflect.TypeOf(person{})
+	cols := []string{"name"}
+	ScannerMapper = mk("X")
+	assert.Equal(t, [][]int{{0}}, std().plan(typ, cols, false, false).fields)
+
+	ScannerMapper = mk("Y")
+	assert.Equal(t, [][]int{{1}}, std().plan(typ, cols, false, false).fields)
+}
+
+func TestPlanMapsFieldsOfEmbeddedPointers(t *testing.T) {
+	type Base struct {
+		Age int
+	}
+	type person struct {
+		*Base
+		Name string
+	}
+
+	s := New(Config{})
+	p := s.plan(reflect.TypeOf(person{}), []string{"age", "name"}, false, false)
+
+	// the embedded pointer is allocated on demand when age is not NULL
+	assert.Equal(t, [][]int{{0, 0}, {1}}, p.fields)
+	assert.Equal(t, []bool{true, false}, p.nullable)
+}
+
+func TestPlanCheckListsUnmappedColumnsAndUnfilledFields(t *testing.T) {
+	type person struct {
+		ID      int    `db:"id"`
+		Name    string `db:"name"`
+		Ignored string `db:"-"`
+		Company struct {
+			Name string `db:"company.name"`
+		}
+	}
+
+	s := New(Config{})
+	p := s.plan(reflect.TypeOf(person{}), []string{"id", "age", "email"}, true, false)
+
+	assert.NoError(t, p.check(false, false))
+
+	err := p.check(true, false)
+	assert.ErrorIs(t, err, ErrStructFieldMissing)
+	assert.Contains(t, err.Error(), `no field for columns "age", "email"`)
+	assert.NotContains(t, err.Error(), "no column for fields")
+
+	err = p.check(false, true)
+	assert.ErrorIs(t, err, ErrStructFieldMissing)
+	assert.Contains(t, err.Error(), "no column for fields Name, Company.Name")
+	assert.NotContains(t, err.Error(), "no field for columns")
+
+	err = p.check(true, true)
+	assert.Contains(t, err.Error(), `no field for columns "age", "email"; no column for fields Name, Company.Name`)
+}
+
+func TestPlanCheckPassesWhenEverythingIsMapped(t *testing.T) {
+	type person struct {
+		ID   int `db:"id"`
+		Name string
+	}
+
+	s := New(Config{})
+	p := s.plan(reflect.TypeOf(person{}), []string{"id", "name"}, false, false)
+
+	assert.NoError(t, p.check(true, true))
+}
@@ -0,0 +1,68 @@
+package scan
+
+import (
+	"fmt"
+	"reflect"
+)
+
+var anySliceType = reflect.TypeOf([]interface{}(nil))
+
+// isPositionalType reports whether the columns of a row are scanned into the
+// elements of t by position, which is the case for []interface{} and for
+// arrays such as [3]string. Byte arrays are left to sql.Scanner and
+// converters since they usually hold a single value, such as a UUID.
+func isPositionalType(t reflect.Type) bool {
+	return t == anySliceType || (t.Kind() == reflect.Array && t.Elem().Kind() != reflect.Uint8)
+}
+
+// positionalDecoder scans the columns of each row by position into a
+// []interface{}, which has an element for each column, or into an array,
+// which must have as many elements as there are columns. The interface{}
+// elements are scanned into Go values chosen from the column types of r.
+func positionalDecoder(r RowsScanner, itemType reflect.Type, cols []string) (decodeFunc, error) {
+	if itemType.Kind() == reflect.Array {
+		if n := itemType.Len(); len(cols) > n {
+			return nil, fmt.Errorf("%d columns for %s: %w", len(cols), itemType, ErrTooManyColumns)
+		} else if len(cols) < n {
+			return nil, fmt.Errorf("%d columns for %s: %w", len(cols), itemType, ErrNotEnoughColumns)
+		}
+	}
+
+	var vals []columnValue
+	if itemType.Elem() == anyType {
+		var err error
+		if vals, err = columnValues(r, len(cols)); err != nil {
+			return nil, err
+		}
+	}
+
+	dests := make([]interface{}, len(cols))
+	return func(item reflect.Value) error {
+		if item.Kind() == reflect.Slice {
+			item.Set(reflect.MakeSlice(itemType, len(cols), len(cols)))
+		}
+		for i := range dests {
+			if vals != nil {
+				dests[i] = vals[i].dest()
+			} else {
+				dests[i] = item.Index(i).Addr().Interface()
+			}
+		}
+
+		if err := r.Scan(dests...); err != nil {
+			se := &ScanError{Type: itemType.Elem(), Err: err}
+			if i := failingColumn(r, dests); i >= 0 {
+				se.Column = cols[i]
+				se.Value = rawValue(r, len(dests), i)
+			}
+			return se
+		}
+
+		for i, v := range vals {
+			if val := v.value(dests[i]); val != nil {
+				item.Index(i).Set(reflect.ValueOf(val))
+			}
+		}
+		return nil
+	}, nil
+}
@@ -0,0 +1,99 @@
+package scan_test
+
+import (
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRowsScansPositionalRows(t *testing.T) {
+	rows := queryTestDB(t, resultSet{
+		Cols:  []string{"id", "name", "score"},
+		Types: []string{"INTEGER", "VARCHAR", "DOUBLE"},
+		Rows: [][]driver.Value{
+			{int64(1), []byte("Brett"), 1.5},
+			{int64(2), nil, nil},
+		},
+	})
+
+	var records [][]interface{}
+	require.NoError(t, scan.Rows(&records, rows))
+	assert.Equal(t, [][]interface{}{
+		{int64(1), "Brett", 1.5},
+		{int64(2), nil, nil},
+	}, records)
+}
+
+func TestRowScansPositionalRow(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+		[]interface{}{int64(2), "Fred"},
+	)
+
+	var record []interface{}
+	require.NoError(t, scan.Row(&record, rows))
+	assert.Equal(t, []interface{}{int64(1), "Brett"}, record)
+}
+
+func TestRowsScansTuples(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"first", "last", "city"},
+		[]interface{}{"Brett", "Jones", "Austin"},
+		[]interface{}{"Fred", "Smith", "Dallas"},
+	)
+
+	var tuples [][3]string
+	require.NoError(t, scan.Rows(&tuples, rows))
+	assert.Equal(t, [][3]string{
+		{"Brett", "Jones", "Austin"},
+		{"Fred", "Smith", "Dallas"},
+	}, tuples)
+}
+
+func TestRowScansTuple(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{int64(1), "Brett"},
+	)
+
+	var tuple [2]interface{}
+	require.NoError(t, scan.Row(&tuple, rows))
+	assert.Equal(t, [2]interface{}{int64(1), "Brett"}, tuple)
+}
+
+func TestRowsTupleErrorsOnColumnCount(t *testing.T) {
+	rows := fakeRowsWithColumns(t, 1, "a", "b", "c")
+
+	var pairs [][2]string
+	err := scan.Rows(&pairs, rows)
+	assert.True(t, errors.Is(err, scan.ErrTooManyColumns))
+
+	rows = fakeRowsWithColumns(t, 1, "a")
+	err = scan.Rows(&pairs, rows)
+	assert.True(t, errors.Is(err, scan.ErrNotEnoughColumns))
+}
+
+func TestRowsTupleReturnsScanErrors(t *testing.T) {
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"a", "b"},
+		Rows: [][]driver.Value{{int64(1), "x"}},
+	})
+
+	var pairs [][2]int
+	err := scan.Rows(&pairs, rows)
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "b", se.Column)
+	assert.Equal(t, 0, se.Row)
+}
+
+func TestRowErrorsForOtherSlices(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id"},
+		[]interface{}{int64(1)},
+	)
+
+	var ids []int64
+	assert.Equal(t, scan.ErrSliceForRow, scan.Row(&ids, rows))
+}
@@ -0,0 +1,92 @@
+package scan_test
+
+import (
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type prefixAddress struct {
+	Street string `db:"street"`
+	City   string
+}
+
+type prefixOrder struct {
+	ID       int           `db:"id"`
+	Billing  prefixAddress `db:"billing_,prefix"`
+	Shipping prefixAddress `db:"shipping_,prefix"`
+}
+
+func TestRowScansPrefixedStructs(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "billing_street", "billing_city", "shipping_street", "shipping_city"},
+		[]interface{}{1, "1 Main St", "Austin", "2 Side St", "Dallas"},
+	)
+
+	var order prefixOrder
+	require.NoError(t, scan.Row(&order, rows))
+	assert.Equal(t, prefixOrder{
+		ID:       1,
+		Billing:  prefixAddress{Street: "1 Main St", City: "Austin"},
+		Shipping: prefixAddress{Street: "2 Side St", City: "Dallas"},
+	}, order)
+}
+
+func TestRowStrictScansPrefixedStructsByTag(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"billing_street", "billing_city"},
+		[]interface{}{"1 Main St", "Austin"},
+	)
+
+	var order prefixOrder
+	require.NoError(t, scan.RowStrict(&order, rows))
+	assert.Equal(t, "1 Main St", order.Billing.Street)
+	assert.Equal(t, "", order.Billing.City)
+}
+
+func TestRowScansNestedPrefixes(t *testing.T) {
+	type geo struct {
+		Lat float64 `db:"lat"`
+	}
+	type address struct {
+		Geo geo `db:"geo_,prefix"`
+	}
+	var item struct {
+		Address address `db:"address_,prefix"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"address_geo_lat"},
+		[]interface{}{1.5},
+	)
+	require.NoError(t, scan.Row(&item, rows))
+	assert.Equal(t, 1.5, item.Address.Geo.Lat)
+
+	cols, err := scan.Columns(&item)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"address_geo_lat"}, cols)
+}
+
+func TestColumnsPrefixesNestedStructs(t *testing.T) {
+	cols, err := scan.Columns(&prefixOrder{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "billing_street", "billing_City", "shipping_street", "shipping_City"}, cols)
+
+	cols, err = scan.ColumnsStrict(&prefixOrder{}, "shipping_street")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "billing_street"}, cols)
+}
+
+func TestValuesPrefixesNestedStructs(t *testing.T) {
+	order := &prefixOrder{
+		ID:       1,
+		Billing:  prefixAddress{Street: "1 Main St", City: "Austin"},
+		Shipping: prefixAddress{Street: "2 Side St", City: "Dallas"},
+	}
+
+	cols, err := scan.Columns(order)
+	require.NoError(t, err)
+
+	vals, err := scan.Values(cols, order)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1, "1 Main St", "Austin", "2 Side St", "Dallas"}, vals)
+}
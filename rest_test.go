@@ -0,0 +1,86 @@
+package scan_test
+
+import (
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type restUser struct {
+	ID    int                    `db:"id"`
+	Name  string                 `db:"name"`
+	Extra map[string]interface{} `db:",rest"`
+}
+
+func TestRowsCollectsUnmappedColumnsIntoRest(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name", "age", "city"},
+		[]interface{}{1, "brett", int64(30), "austin"},
+		[]interface{}{2, "fred", nil, "dallas"},
+	)
+
+	var users []restUser
+	require.NoError(t, scan.Rows(&users, rows))
+	assert.Equal(t, []restUser{
+		{ID: 1, Name: "brett", Extra: map[string]interface{}{"age": int64(30), "city": "austin"}},
+		{ID: 2, Name: "fred", Extra: map[string]interface{}{"age": nil, "city": "dallas"}},
+	}, users)
+}
+
+func TestRowSetsEmptyRestWhenAllColumnsAreMapped(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name"},
+		[]interface{}{1, "brett"},
+	)
+
+	var user restUser
+	require.NoError(t, scan.Row(&user, rows))
+	assert.Equal(t, restUser{ID: 1, Name: "brett", Extra: map[string]interface{}{}}, user)
+}
+
+func TestRestSatisfiesErrorOnUnmappedColumns(t *testing.T) {
+	s := scan.New(scan.Config{ErrorOnUnmappedColumns: true})
+	rows := fakeRowsWithRecords(t, []string{"id", "age"},
+		[]interface{}{1, int64(30)},
+	)
+
+	var user restUser
+	require.NoError(t, s.Row(&user, rows))
+	assert.Equal(t, map[string]interface{}{"age": int64(30)}, user.Extra)
+}
+
+func TestRestInEmbeddedStruct(t *testing.T) {
+	type base struct {
+		Extra map[string]interface{} `db:",rest"`
+	}
+	type item struct {
+		base
+		ID int `db:"id"`
+	}
+
+	rows := fakeRowsWithRecords(t, []string{"id", "color"},
+		[]interface{}{1, "red"},
+	)
+
+	var it item
+	require.NoError(t, scan.Row(&it, rows))
+	assert.Equal(t, 1, it.ID)
+	assert.Equal(t, map[string]interface{}{"color": "red"}, it.Extra)
+}
+
+func TestColumnsExcludesRest(t *testing.T) {
+	cols, err := scan.Columns(&restUser{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "name"}, cols)
+}
+
+func TestValuesReadsRest(t *testing.T) {
+	user := restUser{ID: 1, Extra: map[string]interface{}{"age": 30}}
+
+	vals, err := scan.Values([]string{"id", "age"}, &user)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{1, 30}, vals)
+
+	_, err = scan.Values([]string{"city"}, &user)
+	assert.ErrorIs(t, err, scan.ErrStructFieldMissing)
+}
@@ -0,0 +1,82 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// ErrNoResultSet is returned by ResultSets when there are fewer result sets
+// than destinations.
+var ErrNoResultSet = errors.New("no more result sets")
+
+// ResultSets scans the successive result sets of r into dests, such as the
+// results of a stored procedure or a batch of statements. Every result set is
+// scanned with the usual mapping rules: pointers to slices are scanned like
+// Rows and any other destination like Row. A nil destination skips a result
+// set.
+//
+// r must implement ResultSetsScanner, as *sql.Rows does, when there is more
+// than one destination.
+//
+// With the Lenient setting, the rows which fail to scan are skipped in every
+// result set and their errors are returned in a single *ScanErrors once all
+// the sets are scanned. The Row of each error is relative to its result set
+// and MaxErrors applies to all of them together.
+func ResultSets(r RowsScanner, dests ...interface{}) error {
+	return std().ResultSets(r, dests...)
+}
+
+// ResultSets scans the successive result sets of r into dests. See
+// ResultSets for details.
+func (s *Scanner) ResultSets(r RowsScanner, dests ...interface{}) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
+	}
+
+	if s.cfg.Lenient {
+		shared := *s
+		shared.report = &ScanErrors{}
+		s = &shared
+	}
+
+	for i, dest := range dests {
+		if i > 0 {
+			if err := nextResultSet(r); err != nil {
+				return fmt.Errorf("result set %d: %w", i, err)
+			}
+		}
+		if dest == nil {
+			continue
+		}
+
+		var err error
+		if t := reflect.TypeOf(dest); t.Kind() == reflect.Ptr && t.Elem().Kind() == reflect.Slice {
+			err = s.rows(dest, r, s.cfg.Strict)
+		} else {
+			err = s.row(dest, r, s.cfg.Strict)
+		}
+		if err != nil && (err != s.report || s.report.Aborted) {
+			return fmt.Errorf("result set %d: %w", i, err)
+		}
+	}
+	if s.report != nil && len(s.report.Errors) > 0 {
+		return s.report
+	}
+	return nil
+}
+
+// nextResultSet advances r to its next result set.
+func nextResultSet(r RowsScanner) error {
+	rs, ok := r.(ResultSetsScanner)
+	if !ok {
+		return fmt.Errorf("%T does not support multiple result sets: %w", r, ErrNoResultSet)
+	}
+	if !rs.NextResultSet() {
+		if err := rs.Err(); err != nil {
+			return err
+		}
+		return ErrNoResultSet
+	}
+	return nil
+}
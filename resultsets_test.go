@@ -0,0 +1,132 @@
+package scan_test
+
+import (
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultSetsScansEachSetIntoItsDestination(t *testing.T) {
+	type user struct {
+		ID   int64  `db:"id"`
+		Name string `db:"name"`
+	}
+	type order struct {
+		ID     int64 `db:"id"`
+		UserID int64 `db:"user_id"`
+	}
+
+	rows := queryTestDB(t,
+		resultSet{Cols: []string{"id", "name"}, Rows: [][]driver.Value{{int64(1), "alice"}, {int64(2), "bob"}}},
+		resultSet{Cols: []string{"id", "user_id"}, Rows: [][]driver.Value{{int64(10), int64(1)}}},
+		resultSet{Cols: []string{"count"}, Rows: [][]driver.Value{{int64(3)}}},
+	)
+
+	var (
+		users  []user
+		orders []order
+		count  int
+	)
+	require.NoError(t, scan.ResultSets(rows, &users, &orders, &count))
+	assert.Equal(t, []user{{1, "alice"}, {2, "bob"}}, users)
+	assert.Equal(t, []order{{10, 1}}, orders)
+	assert.Equal(t, 3, count)
+}
+
+func TestResultSetsSkipsNilDestinations(t *testing.T) {
+	rows := queryTestDB(t,
+		resultSet{Cols: []string{"id"}, Rows: [][]driver.Value{{int64(1)}}},
+		resultSet{Cols: []string{"name"}, Rows: [][]driver.Value{{"alice"}}},
+	)
+
+	var name string
+	require.NoError(t, scan.ResultSets(rows, nil, &name))
+	assert.Equal(t, "alice", name)
+}
+
+func TestResultSetsReadsOnlyTheFirstRowOfSingleDestinations(t *testing.T) {
+	rows := queryTestDB(t,
+		resultSet{Cols: []string{"id"}, Rows: [][]driver.Value{{int64(1)}, {int64(2)}}},
+		resultSet{Cols: []string{"id"}, Rows: [][]driver.Value{{int64(3)}}},
+	)
+
+	var first, second int
+	require.NoError(t, scan.ResultSets(rows, &first, &second))
+	assert.Equal(t, 1, first)
+	assert.Equal(t, 3, second)
+}
+
+func TestResultSetsErrorsWhenSetsRunOut(t *testing.T) {
+	rows := queryTestDB(t,
+		resultSet{Cols: []string{"id"}, Rows: [][]driver.Value{{int64(1)}}},
+	)
+
+	var first, second int
+	err := scan.ResultSets(rows, &first, &second)
+	assert.ErrorIs(t, err, scan.ErrNoResultSet)
+	assert.Contains(t, err.Error(), "result set 1")
+	assert.Equal(t, 1, first)
+}
+
+func TestResultSetsErrorsWhenASetIsEmpty(t *testing.T) {
+	rows := queryTestDB(t,
+		resultSet{Cols: []string{"id"}},
+	)
+
+	var id int
+	assert.ErrorIs(t, scan.ResultSets(rows, &id), sql.ErrNoRows)
+}
+
+func TestResultSetsErrorsWithoutNextResultSet(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id"}, []interface{}{1})
+
+	var first, second int
+	assert.ErrorIs(t, scan.ResultSets(rows, &first, &second), scan.ErrNoResultSet)
+	assert.Equal(t, 1, first)
+}
+
+func TestResultSetsCollectsLenientErrorsAcrossSets(t *testing.T) {
+	s := scan.New(scan.Config{Lenient: true})
+	rows := queryTestDB(t,
+		resultSet{Cols: []string{"id"}, Rows: [][]driver.Value{{int64(1)}, {"bad"}}},
+		resultSet{Cols: []string{"name"}, Rows: [][]driver.Value{{"alice"}}},
+		resultSet{Cols: []string{"id"}, Rows: [][]driver.Value{{int64(3)}, {"worse"}}},
+	)
+
+	var (
+		a, b []int64
+		name string
+	)
+	err := s.ResultSets(rows, &a, &name, &b)
+	assert.Equal(t, []int64{1}, a)
+	assert.Equal(t, "alice", name)
+	assert.Equal(t, []int64{3}, b)
+
+	var report *scan.ScanErrors
+	require.True(t, errors.As(err, &report))
+	assert.False(t, report.Aborted)
+	require.Len(t, report.Errors, 2)
+	assert.Equal(t, "bad", report.Errors[0].Value)
+	assert.Equal(t, "worse", report.Errors[1].Value)
+}
+
+func TestResultSetsAbortsAfterMaxErrorsAcrossSets(t *testing.T) {
+	s := scan.New(scan.Config{Lenient: true, MaxErrors: 1})
+	rows := queryTestDB(t,
+		resultSet{Cols: []string{"id"}, Rows: [][]driver.Value{{int64(1)}, {"bad"}}},
+		resultSet{Cols: []string{"id"}, Rows: [][]driver.Value{{"worse"}, {int64(4)}}},
+		resultSet{Cols: []string{"id"}, Rows: [][]driver.Value{{int64(5)}}},
+	)
+
+	var a, b, c []int64
+	err := s.ResultSets(rows, &a, &b, &c)
+	assert.True(t, errors.Is(err, scan.ErrTooManyScanErrors))
+	assert.Equal(t, []int64{1}, a)
+	assert.Empty(t, b)
+	assert.Empty(t, c)
+}
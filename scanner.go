@@ -6,9 +6,6 @@ import (
 	"fmt"
 	"io"
 	"reflect"
-
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 )
 
 var (
@@ -17,9 +14,18 @@ var (
 	// `select col1, col2 from mutable` to []string
 	ErrTooManyColumns = errors.New("too many columns returned for primitive slice")
 
+	// ErrNotEnoughColumns is returned when a query returns fewer columns than
+	// the elements of an array, or the parameters of a Func callback, that
+	// rows are scanned into by position.
+	ErrNotEnoughColumns = errors.New("not enough columns returned")
+
 	// ErrSliceForRow occurs when trying to use Row on a slice
 	ErrSliceForRow = errors.New("cannot scan Row into slice")
 
+	// ErrTooManyRows is returned when a query that must return exactly one row
+	// returns more than one.
+	ErrTooManyRows = errors.New("too many rows returned")
+
 	// AutoClose is true when scan should automatically close Scanner when the scan
 	// is complete. If you set it to false, then you must defer rows.Close() manually
 	AutoClose = true
@@ -30,7 +36,7 @@ var (
 
 	// ScannerMapper transforms database field names into struct/map field names
 	// E.g. you can set function for convert snake_case into CamelCase
-	ScannerMapper = func(name string) string { return cases.Title(language.English).String(name) }
+	ScannerMapper = titleCase
 )
 
 // Row scans a single row into a single variable. It requires that you use
@@ -38,72 +44,155 @@ var (
 // There is no performance impact in using one over the other. QueryRow only
 // defers returning err until Scan is called, which is an unnecessary
 // optimization for this library.
+//
+// Only the first row is read, any remaining rows are ignored. Use
+// RowExactlyOne to make sure that a query returns a single row.
+//
+// v can also be a *map[string]interface{}, in which case every column is
+// stored in the map using a Go type chosen from the column type, or a
+// *[]interface{} or a pointer to an array, in which case the columns are
+// stored by position.
 func Row(v interface{}, r RowsScanner) error {
-	if AutoClose {
-		defer closeRows(r)
-	}
-
-	return row(v, r, false)
+	return std().Row(v, r)
 }
 
 // RowStrict scans a single row into a single variable. It is identical to
 // Row, but it ignores fields that do not have a db tag
 func RowStrict(v interface{}, r RowsScanner) error {
-	if AutoClose {
-		defer closeRows(r)
+	return std().RowStrict(v, r)
+}
+
+// RowExactlyOne scans a single row into a single variable like Row, but it
+// returns ErrTooManyRows, leaving v untouched, when there is more than one
+// row. sql.ErrNoRows is returned when there are no rows.
+func RowExactlyOne(v interface{}, r RowsScanner) error {
+	return std().RowExactlyOne(v, r)
+}
+
+// Rows scans sql rows into a slice (v). The slice can hold structs, primitive
+// types or map[string]interface{} values, or pointers to them. Pointers to
+// structs and maps are allocated for every row. Slices of []interface{} or of
+// arrays, such as [][3]string, hold the columns of every row by position.
+func Rows(v interface{}, r RowsScanner) (outerr error) {
+	return std().Rows(v, r)
+}
+
+// RowsStrict scans sql rows into a slice (v) only using db tags
+func RowsStrict(v interface{}, r RowsScanner) (outerr error) {
+	return std().RowsStrict(v, r)
+}
+
+// Row scans a single row into a single variable. See Row for details.
+func (s *Scanner) Row(v interface{}, r RowsScanner) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
 	}
 
-	return row(v, r, true)
+	return s.row(v, r, s.cfg.Strict)
 }
 
-func row(v interface{}, r RowsScanner, strict bool) error {
-	vType := reflect.TypeOf(v)
-	if k := vType.Kind(); k != reflect.Ptr {
-		return fmt.Errorf("%q must be a pointer: %w", k.String(), ErrNotAPointer)
+// RowStrict is identical to Row, but it ignores fields that are not tagged
+// with the configured tag name.
+func (s *Scanner) RowStrict(v interface{}, r RowsScanner) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
 	}
 
-	vType = vType.Elem()
-	vVal := reflect.ValueOf(v).Elem()
-	if vType.Kind() == reflect.Slice {
-		return ErrSliceForRow
+	return s.row(v, r, true)
+}
+
+// RowExactlyOne is identical to Row, but it returns ErrTooManyRows when there
+// is more than one row. See RowExactlyOne for details.
+func (s *Scanner) RowExactlyOne(v interface{}, r RowsScanner) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
 	}
 
-	sl := reflect.New(reflect.SliceOf(vType))
-	err := rows(sl.Interface(), r, strict)
+	vVal, err := rowValue(v)
 	if err != nil {
 		return err
 	}
 
-	sl = sl.Elem()
-
-	if sl.Len() == 0 {
-		return sql.ErrNoRows
+	item, err := s.first(r, vVal.Type(), s.cfg.Strict)
+	if err != nil {
+		return err
+	}
+	if r.Next() {
+		return ErrTooManyRows
+	}
+	if err := r.Err(); err != nil {
+		return err
 	}
 
-	vVal.Set(sl.Index(0))
-
+	vVal.Set(item)
 	return nil
 }
 
 // Rows scans sql rows into a slice (v)
-func Rows(v interface{}, r RowsScanner) (outerr error) {
-	if AutoClose {
-		defer closeRows(r)
+func (s *Scanner) Rows(v interface{}, r RowsScanner) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
 	}
 
-	return rows(v, r, false)
+	return s.rows(v, r, s.cfg.Strict)
 }
 
-// RowsStrict scans sql rows into a slice (v) only using db tags
-func RowsStrict(v interface{}, r RowsScanner) (outerr error) {
-	if AutoClose {
-		defer closeRows(r)
+// RowsStrict scans sql rows into a slice (v) only using the configured tag
+// name.
+func (s *Scanner) RowsStrict(v interface{}, r RowsScanner) error {
+	if !s.cfg.DisableAutoClose {
+		defer s.closeRows(r)
+	}
+
+	return s.rows(v, r, true)
+}
+
+func (s *Scanner) row(v interface{}, r RowsScanner, strict bool) error {
+	vVal, err := rowValue(v)
+	if err != nil {
+		return err
+	}
+
+	item, err := s.first(r, vVal.Type(), strict)
+	if err != nil {
+		return err
 	}
 
-	return rows(v, r, true)
+	vVal.Set(item)
+	return nil
+}
+
+// rowValue returns the value that v points to, which must not be a slice
+// other than []interface{}.
+func rowValue(v interface{}) (reflect.Value, error) {
+	vType := reflect.TypeOf(v)
+	if k := vType.Kind(); k != reflect.Ptr {
+		return reflect.Value{}, fmt.Errorf("%q must be a pointer: %w", k.String(), ErrNotAPointer)
+	}
+	if t := vType.Elem(); t.Kind() == reflect.Slice && t != anySliceType {
+		return reflect.Value{}, ErrSliceForRow
+	}
+	return reflect.ValueOf(v).Elem(), nil
+}
+
+// first scans the first row of r into a new value of itemType and stops
+// reading. sql.ErrNoRows is returned when r has no rows.
+func (s *Scanner) first(r RowsScanner, itemType reflect.Type, strict bool) (reflect.Value, error) {
+	var first reflect.Value
+	err := s.each(r, itemType, strict, false, func(item reflect.Value) error {
+		first = item
+		return errStopIteration
+	})
+	if err != nil && !errors.Is(err, errStopIteration) {
+		return reflect.Value{}, err
+	}
+	if !first.IsValid() {
+		return reflect.Value{}, sql.ErrNoRows
+	}
+	return first, nil
 }
 
-func rows(v interface{}, r RowsScanner, strict bool) (outerr error) {
+func (s *Scanner) rows(v interface{}, r RowsScanner, strict bool) (outerr error) {
 	vType := reflect.TypeOf(v)
 	if k := vType.Kind(); k != reflect.Ptr {
 		return fmt.Errorf("%q must be a pointer: %w", k.String(), ErrNotAPointer)
@@ -114,91 +203,216 @@ func rows(v interface{}, r RowsScanner, strict bool) (outerr error) {
 	}
 
 	sliceVal := reflect.Indirect(reflect.ValueOf(v))
-	itemType := sliceType.Elem()
+	return s.each(r, sliceType.Elem(), strict, s.cfg.Lenient, func(item reflect.Value) error {
+		sliceVal.Set(reflect.Append(sliceVal, item))
+		return nil
+	})
+}
+
+// decodeFunc scans the current row into item.
+type decodeFunc func(item reflect.Value) error
 
+// each scans every row of r into a new value of itemType and passes it to fn.
+// Iteration stops at the first error, including errors returned by fn, unless
+// lenient is true, in which case the rows which fail to scan are skipped as
+// described by Config.Lenient.
+func (s *Scanner) each(r RowsScanner, itemType reflect.Type, strict, lenient bool, fn func(item reflect.Value) error) error {
 	cols, err := r.Columns()
 	if err != nil {
 		return err
 	}
+	if len(cols) == 0 {
+		return nil
+	}
 
-	isPrimitive := itemType.Kind() != reflect.Struct
-
-	for r.Next() {
-		sliceItem := reflect.New(itemType).Elem()
+	decode, err := s.decoder(r, itemType, cols, strict)
+	if err != nil {
+		return err
+	}
+	return s.decodeRows(r, itemType, decode, lenient, fn)
+}
 
-		var pointers []interface{}
-		if isPrimitive {
-			if len(cols) > 1 {
-				return ErrTooManyColumns
+// decodeRows decodes every row of r into a new value of itemType and passes
+// it to fn. When lenient is true, the rows which fail to scan are skipped and
+// their errors are returned in a *ScanErrors at the end, along with those
+// already in s.report.
+func (s *Scanner) decodeRows(r RowsScanner, itemType reflect.Type, decode decodeFunc, lenient bool, fn func(item reflect.Value) error) error {
+	var report *ScanErrors
+	if lenient {
+		report = s.report
+	}
+	for row := 0; r.Next(); row++ {
+		item := reflect.New(itemType).Elem()
+		if err := decode(item); err != nil {
+			se, ok := err.(*ScanError)
+			if !ok {
+				return err
+			}
+			se.Row = row
+			if !lenient {
+				return err
 			}
-			pointers = []interface{}{sliceItem.Addr().Interface()}
-		} else {
-			pointers = structPointers(sliceItem, cols, strict)
+			if report == nil {
+				report = &ScanErrors{}
+			}
+			if report.add(se, s.cfg.MaxErrors) {
+				return report
+			}
+			continue
 		}
-
-		if len(pointers) == 0 {
-			return nil
+		if err := fn(item); err != nil {
+			return err
 		}
+	}
+	if err := r.Err(); err != nil {
+		return err
+	}
+	if report != nil && len(report.Errors) > 0 {
+		return report
+	}
+	return nil
+}
+
+// decoder returns the decodeFunc used to scan rows with cols into values of
+// itemType.
+func (s *Scanner) decoder(r RowsScanner, itemType reflect.Type, cols []string, strict bool) (decodeFunc, error) {
+	if conv, _ := s.converters.scanner(itemType); conv != nil {
+		return s.primitiveDecoder(r, itemType, cols), nil
+	}
 
-		err := r.Scan(pointers...)
+	if base := indirectType(itemType); base != itemType && (isStructType(base) || isMapType(base)) {
+		// pointers to structs and maps are allocated and scanned as the value
+		// they point to
+		decode, err := s.decoder(r, base, cols, strict)
 		if err != nil {
-			return err
+			return nil, err
 		}
-		sliceVal.Set(reflect.Append(sliceVal, sliceItem))
+		return func(item reflect.Value) error {
+			return decode(allocIndirect(item))
+		}, nil
+	}
+
+	switch {
+	case isStructType(itemType):
+		return s.structDecoder(r, itemType, cols, strict)
+	case isMapType(itemType):
+		return mapDecoder(r, cols)
+	case isPositionalType(itemType):
+		return positionalDecoder(r, itemType, cols)
+	case isVariantType(itemType):
+		return s.variantDecoder(r, itemType, cols, strict)
+	default:
+		return s.primitiveDecoder(r, itemType, cols), nil
 	}
-	return r.Err()
 }
 
-// Initialization the tags from struct.
-func initFieldTag(sliceItem reflect.Value, fieldTagMap *map[string]reflect.Value) {
-	typ := sliceItem.Type()
-	for i := 0; i < sliceItem.NumField(); i++ {
-		if typ.Field(i).Anonymous || typ.Field(i).Type.Kind() == reflect.Struct {
-			// found an embedded struct
-			sliceItemOfAnonymous := sliceItem.Field(i)
-			initFieldTag(sliceItemOfAnonymous, fieldTagMap)
-		}
-		tag, ok := typ.Field(i).Tag.Lookup("db")
-		if ok && tag != "" {
-			(*fieldTagMap)[tag] = sliceItem.Field(i)
+// isStructType reports whether t is a struct whose fields are scanned
+// individually, as opposed to a struct which scans a whole column such as
+// time.Time or sql.NullString.
+func isStructType(t reflect.Type) bool {
+	if t.Kind() != reflect.Struct || t == timeType {
+		return false
+	}
+	return !reflect.PointerTo(t).Implements(sqlScannerType)
+}
+
+// indirectType returns the type that t points to through any number of
+// pointers.
+func indirectType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// allocIndirect follows v through any number of pointers, allocating the nil
+// ones, and returns the value that is pointed to.
+func allocIndirect(v reflect.Value) reflect.Value {
+	for v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			v.Set(reflect.New(v.Type().Elem()))
 		}
+		v = v.Elem()
 	}
+	return v
+}
+
+func (s *Scanner) structDecoder(r RowsScanner, itemType reflect.Type, cols []string, strict bool) (decodeFunc, error) {
+	return s.planDecoder(r, s.plan(itemType, cols, strict, false))
 }
 
-func structPointers(sliceItem reflect.Value, cols []string, strict bool) []interface{} {
-	pointers := make([]interface{}, 0, len(cols))
-	fieldTag := make(map[string]reflect.Value, len(cols))
-	initFieldTag(sliceItem, &fieldTag)
+// planDecoder returns a decodeFunc which scans rows into structs as described
+// by plan.
+func (s *Scanner) planDecoder(r RowsScanner, plan *scanPlan) (decodeFunc, error) {
+	if err := plan.check(s.cfg.ErrorOnUnmappedColumns, s.cfg.ErrorOnUnfilledFields); err != nil {
+		return nil, err
+	}
 
-	for _, colName := range cols {
-		var fieldVal reflect.Value
-		if v, ok := fieldTag[colName]; ok {
-			fieldVal = v
-		} else {
-			if strict {
-				fieldVal = reflect.ValueOf(nil)
-			} else {
-				fieldVal = sliceItem.FieldByName(ScannerMapper(colName))
+	var rest []columnValue
+	if plan.rest != nil {
+		var err error
+		if rest, err = columnValues(r, len(plan.cols)); err != nil {
+			return nil, err
+		}
+	}
+	dests := plan.newDests(s.converters, rest)
+
+	return func(item reflect.Value) error {
+		pointers := dests.prepare(item)
+		if err := r.Scan(pointers...); err != nil {
+			i := failingColumn(r, pointers)
+			se := plan.scanError(i, err)
+			if i >= 0 {
+				se.Value = rawValue(r, len(pointers), i)
 			}
+			return se
 		}
-		if !fieldVal.IsValid() || !fieldVal.CanSet() {
-			// have to add if we found a column because Scan() requires
-			// len(cols) arguments or it will error. This way we can scan to
-			// a useless pointer
-			var nothing interface{}
-			pointers = append(pointers, &nothing)
-			continue
+		return dests.finish(item)
+	}, nil
+}
+
+func (s *Scanner) primitiveDecoder(r RowsScanner, itemType reflect.Type, cols []string) decodeFunc {
+	conv, deref := s.converters.scanner(itemType)
+	k := itemType.Kind()
+	nullZero := s.cfg.NullZero && k != reflect.Ptr && k != reflect.Interface
+	return func(item reflect.Value) error {
+		if len(cols) > 1 {
+			return ErrTooManyColumns
+		}
+		if conv == nil {
+			dest := item.Addr()
+			if nullZero {
+				// a pointer to a pointer is set to nil when the column is NULL
+				dest = reflect.New(item.Addr().Type())
+			}
+			if err := r.Scan(dest.Interface()); err != nil {
+				return &ScanError{Column: cols[0], Type: item.Type(), Value: rawValue(r, 1, 0), Err: err}
+			}
+			if nullZero && !dest.Elem().IsNil() {
+				item.Set(dest.Elem().Elem())
+			}
+			return nil
 		}
 
-		pointers = append(pointers, fieldVal.Addr().Interface())
+		var src interface{}
+		err := r.Scan(&src)
+		if err == nil && (src != nil || !(deref || nullZero)) {
+			var v interface{}
+			if v, err = conv(src); err == nil {
+				err = setConverted(item, v)
+			}
+		}
+		if err != nil {
+			return &ScanError{Column: cols[0], Type: item.Type(), Value: src, Err: err}
+		}
+		return nil
 	}
-	return pointers
 }
 
-func closeRows(c io.Closer) {
+func (s *Scanner) closeRows(c io.Closer) {
 	if err := c.Close(); err != nil {
-		if OnAutoCloseError != nil {
-			OnAutoCloseError(err)
+		if s.cfg.OnAutoCloseError != nil {
+			s.cfg.OnAutoCloseError(err)
 		}
 	}
 }
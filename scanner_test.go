@@ -7,6 +7,7 @@ import (
 	"sync"
 	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/blockloop/scan/v2"
 	"github.com/stretchr/testify/assert"
@@ -70,7 +71,14 @@ func TestErrorsWhenScanErrors(t *testing.T) {
 	}
 
 	err := scan.Row(&item, rows)
-	assert.Equal(t, expected, err)
+	assert.ErrorIs(t, err, expected)
+
+	var se *scan.ScanError
+	require.ErrorAs(t, err, &se)
+	assert.Equal(t, 0, se.Row)
+	assert.Equal(t, "first_and_last_name", se.Column)
+	assert.Equal(t, "FirstAndLastName", se.Field)
+	assert.Equal(t, reflect.TypeOf(""), se.Type)
 }
 
 func TestRowsErrorsWhenNotGivenAPointer(t *testing.T) {
@@ -251,6 +259,62 @@ func TestRowReturnsErrNoRowsWhenQueryHasNoRows(t *testing.T) {
 	assert.EqualValues(t, sql.ErrNoRows, scan.Row(&item, rows))
 }
 
+func TestRowStopsAfterFirstRow(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+		[]interface{}{"Fred"},
+		[]interface{}{"Stacy"},
+	)
+
+	var name string
+	require.NoError(t, scan.Row(&name, rows))
+	assert.Equal(t, "Brett", name)
+	assert.Equal(t, 1, rows.NextCallCount())
+	assert.Equal(t, 1, rows.ScanCallCount())
+}
+
+func TestRowExactlyOneScansOnlyRow(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+	)
+
+	var name string
+	require.NoError(t, scan.RowExactlyOne(&name, rows))
+	assert.Equal(t, "Brett", name)
+}
+
+func TestRowExactlyOneReturnsErrTooManyRows(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+		[]interface{}{"Fred"},
+		[]interface{}{"Stacy"},
+	)
+
+	var name string
+	assert.Equal(t, scan.ErrTooManyRows, scan.RowExactlyOne(&name, rows))
+	assert.Equal(t, "", name)
+	assert.Equal(t, 2, rows.NextCallCount())
+	assert.Equal(t, 1, rows.ScanCallCount())
+}
+
+func TestRowExactlyOneReturnsErrNoRows(t *testing.T) {
+	rows := fakeRowsWithColumns(t, 0, "name")
+
+	var name string
+	assert.Equal(t, sql.ErrNoRows, scan.RowExactlyOne(&name, rows))
+}
+
+func TestRowExactlyOneReturnsRowsError(t *testing.T) {
+	rowsErr := errors.New("broken")
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{"Brett"},
+	)
+	rows.ErrReturns(rowsErr)
+
+	var name string
+	assert.Equal(t, rowsErr, scan.RowExactlyOne(&name, rows))
+}
+
 func TestRowErrorsWhenItemIsNotAPointer(t *testing.T) {
 	rows := &FakeRowsScanner{}
 
@@ -362,8 +426,235 @@ func Test_OnAutoCloseErrorIsCalledWhenRowsCloseErrors(t *testing.T) {
 	assert.EqualValues(t, 1, calls)
 }
 
+func TestRowsScansSliceOfStructPointers(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"First", "Last"},
+		[]interface{}{"Brett", "Jones"},
+		[]interface{}{"Fred", "Jones"},
+	)
+
+	type person struct {
+		First string
+		Last  string
+	}
+	var items []*person
+
+	require.NoError(t, scan.Rows(&items, rows))
+	require.Len(t, items, 2)
+	assert.Equal(t, &person{"Brett", "Jones"}, items[0])
+	assert.Equal(t, &person{"Fred", "Jones"}, items[1])
+}
+
+func TestRowsScansMultipleLevelsOfIndirection(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"First"},
+		[]interface{}{"Brett"},
+	)
+
+	var items []**struct {
+		First string
+	}
+
+	require.NoError(t, scan.Rows(&items, rows))
+	require.Len(t, items, 1)
+	assert.Equal(t, "Brett", (**items[0]).First)
+}
+
+func TestRowAllocatesStructPointer(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"First"},
+		[]interface{}{"Brett"},
+	)
+
+	var item *struct {
+		First string
+	}
+
+	require.NoError(t, scan.Row(&item, rows))
+	require.NotNil(t, item)
+	assert.Equal(t, "Brett", item.First)
+}
+
+func TestRowsScansPrimitivePointers(t *testing.T) {
+	name := "Brett"
+	rows := fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{&name},
+		[]interface{}{nil},
+	)
+
+	var names []*string
+
+	require.NoError(t, scan.Rows(&names, rows))
+	assert.Equal(t, []*string{&name, nil}, names)
+}
+
+func TestRowsScansStructsWhichScanWholeColumns(t *testing.T) {
+	now := time.Now()
+	rows := fakeRowsWithRecords(t, []string{"created"},
+		[]interface{}{now},
+	)
+
+	var created []time.Time
+
+	require.NoError(t, scan.Rows(&created, rows))
+	assert.Equal(t, []time.Time{now}, created)
+
+	rows = fakeRowsWithRecords(t, []string{"name"},
+		[]interface{}{sql.NullString{String: "Brett", Valid: true}},
+	)
+
+	var names []sql.NullString
+
+	require.NoError(t, scan.Rows(&names, rows))
+	assert.Equal(t, []sql.NullString{{String: "Brett", Valid: true}}, names)
+}
+
+func TestRowsMapsDuplicateColumnsPositionally(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "name", "id", "name"},
+		[]interface{}{1, "Brett", 2, "Costco"},
+	)
+
+	var item struct {
+		ID      int    `db:"id"`
+		Name    string `db:"name"`
+		Company struct {
+			ID   int    `db:"id"`
+			Name string `db:"name"`
+		}
+	}
+
+	require.NoError(t, scan.Row(&item, rows))
+	assert.Equal(t, 1, item.ID)
+	assert.Equal(t, "Brett", item.Name)
+	assert.Equal(t, 2, item.Company.ID)
+	assert.Equal(t, "Costco", item.Company.Name)
+}
+
+func TestRowsMapsDuplicateColumnsToOuterFieldsFirst(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "id"},
+		[]interface{}{int64(1), int64(2)},
+	)
+
+	var item struct {
+		Company struct {
+			ID int64 `db:"id"`
+		}
+		ID int64 `db:"id"`
+	}
+
+	require.NoError(t, scan.Row(&item, rows))
+	assert.Equal(t, int64(1), item.ID)
+	assert.Equal(t, int64(2), item.Company.ID)
+}
+
+func TestRowsMapsDuplicateColumnsByOccurrence(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "id"},
+		[]interface{}{1, 2},
+	)
+
+	var item struct {
+		Company struct {
+			ID int `db:"id,occurrence=2"`
+		}
+		ID int `db:"id"`
+	}
+
+	require.NoError(t, scan.Row(&item, rows))
+	assert.Equal(t, 1, item.ID)
+	assert.Equal(t, 2, item.Company.ID)
+}
+
+func TestRowsDoesNotOverwriteFieldWithDuplicateColumn(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "id"},
+		[]interface{}{1, 2},
+	)
+
+	var item struct {
+		ID int `db:"id"`
+	}
+
+	require.NoError(t, scan.Row(&item, rows))
+	assert.Equal(t, 1, item.ID)
+}
+
+func TestRowStrictErrorsOnAmbiguousDuplicateColumns(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "id"},
+		[]interface{}{1, 2},
+	)
+
+	var item struct {
+		ID int `db:"id"`
+	}
+
+	err := scan.RowStrict(&item, rows)
+	assert.ErrorIs(t, err, scan.ErrStructFieldMissing)
+	assert.Contains(t, err.Error(), `duplicate columns "id"`)
+}
+
+type employee struct {
+	ID      int       `db:"id"`
+	Name    *string   `db:"name"`
+	Manager *employee `db:"manager_,prefix"`
+}
+
+func TestRowsAllocatesPointerStructsForNonNullColumns(t *testing.T) {
+	fred, two := "Fred", 2
+	namePtr := &fred
+	// columns of the manager are scanned into pointers to their fields
+	rows := fakeRowsWithRecords(t, []string{"id", "name", "manager_id", "manager_name"},
+		[]interface{}{1, nil, &two, &namePtr},
+		[]interface{}{2, &fred, nil, nil},
+	)
+
+	var items []employee
+	require.NoError(t, scan.Rows(&items, rows))
+	require.Len(t, items, 2)
+
+	require.NotNil(t, items[0].Manager)
+	assert.Equal(t, 2, items[0].Manager.ID)
+	assert.Equal(t, &fred, items[0].Manager.Name)
+	assert.Nil(t, items[0].Manager.Manager)
+
+	assert.Nil(t, items[1].Manager)
+}
+
+func TestRowsScansPromotedFieldsOfEmbeddedPointers(t *testing.T) {
+	type Base struct {
+		Age int
+	}
+	type person struct {
+		*Base
+		Name string
+	}
+
+	age := 40
+	rows := fakeRowsWithRecords(t, []string{"age", "name"},
+		[]interface{}{&age, "Brett"},
+		[]interface{}{nil, "Fred"},
+	)
+
+	var items []person
+	require.NoError(t, scan.Rows(&items, rows))
+	require.Len(t, items, 2)
+	require.NotNil(t, items[0].Base)
+	assert.Equal(t, 40, items[0].Age)
+	assert.Equal(t, "Brett", items[0].Name)
+	assert.Nil(t, items[1].Base)
+	assert.Equal(t, "Fred", items[1].Name)
+}
+
 func setValue(ptr, val interface{}) {
-	reflect.ValueOf(ptr).Elem().Set(reflect.ValueOf(val))
+	dest := reflect.ValueOf(ptr).Elem()
+	if val == nil {
+		dest.Set(reflect.Zero(dest.Type()))
+		return
+	}
+	v := reflect.ValueOf(val)
+	if dest.Kind() == reflect.Ptr && !v.Type().AssignableTo(dest.Type()) {
+		// like sql.Rows, allocate pointers for non-NULL values
+		p := reflect.New(dest.Type().Elem())
+		setValue(p.Interface(), val)
+		dest.Set(p)
+		return
+	}
+	dest.Set(v)
 }
 
 type simpleQueue struct {
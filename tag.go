@@ -0,0 +1,155 @@
+package scan
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// tagOptions is the comma-separated list of options that follows the column
+// name in a struct tag, e.g. `db:"id,occurrence=2"`.
+type tagOptions string
+
+// parseTag splits a struct tag into the column name and its options.
+func parseTag(tag string) (string, tagOptions) {
+	name, opts, _ := strings.Cut(tag, ",")
+	return name, tagOptions(opts)
+}
+
+// Contains reports whether the options contain the flag or key name.
+func (o tagOptions) Contains(name string) bool {
+	_, ok := o.Lookup(name)
+	return ok
+}
+
+// Lookup returns the value of the option name, which is empty for flags.
+func (o tagOptions) Lookup(name string) (string, bool) {
+	s := string(o)
+	for s != "" {
+		var opt string
+		opt, s, _ = strings.Cut(s, ",")
+		key, value, _ := strings.Cut(opt, "=")
+		if key == name {
+			return value, true
+		}
+	}
+	return "", false
+}
+
+// occurrence returns the 1-based occurrence of a duplicated column name that
+// a field is mapped to with the occurrence option, or 0 when it is not set.
+func (o tagOptions) occurrence() int {
+	v, ok := o.Lookup("occurrence")
+	if !ok {
+		return 0
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 1 {
+		return 0
+	}
+	return n
+}
+
+// prefix returns the column name prefix of a nested struct or collection
+// field which is tagged with the prefix option, e.g. `db:"billing_,prefix"`.
+func (s *Scanner) prefix(field reflect.StructField) (string, bool) {
+	if nestedType(field.Type).Kind() != reflect.Struct {
+		return "", false
+	}
+	tag, ok := field.Tag.Lookup(s.cfg.TagName)
+	if !ok {
+		return "", false
+	}
+	name, opts := parseTag(tag)
+	if !opts.Contains("prefix") {
+		return "", false
+	}
+	return name, true
+}
+
+// isJSON reports whether field is tagged with the json option, e.g.
+// `db:"settings,json"`, in which case its column holds the field encoded as
+// JSON.
+func (s *Scanner) isJSON(field reflect.StructField) bool {
+	tag, ok := field.Tag.Lookup(s.cfg.TagName)
+	if !ok {
+		return false
+	}
+	_, opts := parseTag(tag)
+	return opts.Contains("json")
+}
+
+// isNullZero reports whether field is scanned as its zero value when its
+// column is NULL. This is the case when field is tagged with the nullzero
+// option, e.g. `db:"nickname,nullzero"`, or when the NullZero setting is on,
+// except for pointers and interfaces which hold NULL as nil.
+func (s *Scanner) isNullZero(field reflect.StructField) bool {
+	if k := field.Type.Kind(); k == reflect.Ptr || k == reflect.Interface {
+		return false
+	}
+	return s.cfg.NullZero || s.hasNullZeroTag(field)
+}
+
+// hasNullZeroTag reports whether field is tagged with the nullzero option, in
+// which case Values writes NULL for its zero value. The NullZero setting does
+// not apply to Values so that false and 0 are not written as NULL.
+func (s *Scanner) hasNullZeroTag(field reflect.StructField) bool {
+	tag, ok := field.Tag.Lookup(s.cfg.TagName)
+	if !ok {
+		return false
+	}
+	_, opts := parseTag(tag)
+	return opts.Contains("nullzero")
+}
+
+// occurrences assigns fields sharing a column name to the successive
+// occurrences of that column in a result set, which happens when joined tables
+// have columns with the same name. Fields with the occurrence option take the
+// occurrence they name and the remaining fields take the free occurrences
+// outer fields first, so that the first occurrence goes to the outer struct
+// and the next ones to its nested and embedded structs, and in declaration
+// order among fields of the same depth. The result holds the index path of
+// the field for each occurrence of a column, with nil for unassigned
+// occurrences.
+func occurrences(fields []taggedField) map[string][][]int {
+	slots := make(map[string][][]int, len(fields))
+
+	for _, f := range fields {
+		if f.occurrence == 0 {
+			continue
+		}
+		s := slots[f.column]
+		for len(s) < f.occurrence {
+			s = append(s, nil)
+		}
+		if s[f.occurrence-1] == nil {
+			s[f.occurrence-1] = f.index
+		}
+		slots[f.column] = s
+	}
+
+	var free []taggedField
+	for _, f := range fields {
+		if f.occurrence == 0 {
+			free = append(free, f)
+		}
+	}
+	sort.SliceStable(free, func(i, j int) bool {
+		return len(free[i].index) < len(free[j].index)
+	})
+
+	for _, f := range free {
+		s := slots[f.column]
+		free := 0
+		for free < len(s) && s[free] != nil {
+			free++
+		}
+		if free == len(s) {
+			s = append(s, nil)
+		}
+		s[free] = f.index
+		slots[f.column] = s
+	}
+	return slots
+}
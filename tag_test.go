@@ -0,0 +1,83 @@
+package scan
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseTag(t *testing.T) {
+	name, opts := parseTag("id")
+	assert.Equal(t, "id", name)
+	assert.Equal(t, tagOptions(""), opts)
+
+	name, opts = parseTag("id,occurrence=2,pk")
+	assert.Equal(t, "id", name)
+	assert.True(t, opts.Contains("pk"))
+	assert.True(t, opts.Contains("occurrence"))
+	assert.False(t, opts.Contains("occ"))
+	assert.Equal(t, 2, opts.occurrence())
+
+	name, opts = parseTag(",json")
+	assert.Equal(t, "", name)
+	assert.True(t, opts.Contains("json"))
+	assert.Equal(t, 0, opts.occurrence())
+}
+
+func TestTagOptionsLookup(t *testing.T) {
+	opts := tagOptions("prefix=billing_,json")
+
+	v, ok := opts.Lookup("prefix")
+	assert.True(t, ok)
+	assert.Equal(t, "billing_", v)
+
+	v, ok = opts.Lookup("json")
+	assert.True(t, ok)
+	assert.Equal(t, "", v)
+
+	_, ok = opts.Lookup("missing")
+	assert.False(t, ok)
+}
+
+func TestOccurrenceIgnoresInvalidValues(t *testing.T) {
+	assert.Equal(t, 0, tagOptions("occurrence=0").occurrence())
+	assert.Equal(t, 0, tagOptions("occurrence=x").occurrence())
+	assert.Equal(t, 0, tagOptions("occurrence").occurrence())
+}
+
+func TestOccurrencesAssignsFieldsInOrder(t *testing.T) {
+	slots := occurrences([]taggedField{
+		{column: "id", index: []int{0}},
+		{column: "name", index: []int{1}},
+		{column: "id", index: []int{2, 0}},
+	})
+
+	assert.Equal(t, map[string][][]int{
+		"id":   {{0}, {2, 0}},
+		"name": {{1}},
+	}, slots)
+}
+
+func TestOccurrencesAssignsOuterFieldsFirst(t *testing.T) {
+	slots := occurrences([]taggedField{
+		{column: "id", index: []int{0, 1, 0}},
+		{column: "id", index: []int{0, 0}},
+		{column: "id", index: []int{1}},
+	})
+
+	assert.Equal(t, map[string][][]int{
+		"id": {{1}, {0, 0}, {0, 1, 0}},
+	}, slots)
+}
+
+func TestOccurrencesPrefersExplicitOccurrences(t *testing.T) {
+	slots := occurrences([]taggedField{
+		{column: "id", index: []int{0}, occurrence: 2},
+		{column: "id", index: []int{1}},
+		{column: "id", index: []int{2}, occurrence: 4},
+	})
+
+	assert.Equal(t, map[string][][]int{
+		"id": {{1}, {0}, nil, {2}},
+	}, slots)
+}
@@ -0,0 +1,149 @@
+package scan
+
+import (
+	"bytes"
+	"encoding"
+	"fmt"
+	"reflect"
+)
+
+var (
+	textMarshalerType     = reflect.TypeOf((*encoding.TextMarshaler)(nil)).Elem()
+	textUnmarshalerType   = reflect.TypeOf((*encoding.TextUnmarshaler)(nil)).Elem()
+	binaryUnmarshalerType = reflect.TypeOf((*encoding.BinaryUnmarshaler)(nil)).Elem()
+)
+
+// isUnmarshalerType reports whether values of t are scanned with their
+// UnmarshalText or UnmarshalBinary method. Types which database/sql scans
+// itself, such as time.Time and sql.Scanner implementations, are excluded.
+func isUnmarshalerType(t reflect.Type) bool {
+	if t == timeType || t.Kind() == reflect.Ptr {
+		return false
+	}
+	pt := reflect.PointerTo(t)
+	if pt.Implements(sqlScannerType) {
+		return false
+	}
+	return pt.Implements(textUnmarshalerType) || pt.Implements(binaryUnmarshalerType)
+}
+
+// isTextMarshalerType reports whether values of t are passed to the database
+// as the result of their MarshalText method. Types which database/sql
+// converts itself, such as time.Time and driver.Valuer implementations, are
+// excluded.
+func isTextMarshalerType(t reflect.Type) bool {
+	if t == timeType || t.Kind() == reflect.Ptr {
+		return false
+	}
+	pt := reflect.PointerTo(t)
+	if t.Implements(valuerType) || pt.Implements(valuerType) {
+		return false
+	}
+	return pt.Implements(textMarshalerType)
+}
+
+// unmarshalConverter returns a converter which scans columns into values of
+// t, or of the type t points to. Values which can be assigned to t as they
+// are, such as an int64 into an int enum or a []byte into a byte slice type,
+// are assigned directly. Other values use UnmarshalText for text and
+// UnmarshalBinary for bytes which are not valid text for the type.
+func unmarshalConverter(t reflect.Type) (fn ConvertFunc, deref bool) {
+	if t.Kind() == reflect.Ptr {
+		deref = true
+		t = t.Elem()
+	}
+	if !isUnmarshalerType(t) {
+		return nil, false
+	}
+
+	return func(src interface{}) (interface{}, error) {
+		if src == nil {
+			return nil, nil
+		}
+		if v, ok := assignDirect(t, src); ok {
+			return v.Interface(), nil
+		}
+
+		v := reflect.New(t)
+		var err error
+		switch src := src.(type) {
+		case string:
+			err = unmarshal(v.Interface(), []byte(src), false)
+		case []byte:
+			err = unmarshal(v.Interface(), src, true)
+		default:
+			err = fmt.Errorf("cannot unmarshal %T into %s", src, t)
+		}
+		if err != nil {
+			return nil, err
+		}
+		return v.Elem().Interface(), nil
+	}, deref
+}
+
+// assignDirect returns src as a value of t when database/sql would assign it
+// to t without the help of UnmarshalText, i.e. when both have the same kind or
+// src is an int64 which fits into the integer type t. Bytes are copied since
+// the driver may reuse them.
+func assignDirect(t reflect.Type, src interface{}) (reflect.Value, bool) {
+	sv := reflect.ValueOf(src)
+	v := reflect.New(t).Elem()
+
+	switch t.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if sv.Kind() != reflect.Int64 || v.OverflowInt(sv.Int()) {
+			return v, false
+		}
+		v.SetInt(sv.Int())
+		return v, true
+	}
+	if sv.Kind() != t.Kind() || !sv.Type().ConvertibleTo(t) {
+		return v, false
+	}
+	if b, ok := src.([]byte); ok {
+		sv = reflect.ValueOf(bytes.Clone(b))
+	}
+	v.Set(sv.Convert(t))
+	return v, true
+}
+
+// unmarshal decodes b into v with UnmarshalText, or with UnmarshalBinary
+// when v only implements that or when binary is true and UnmarshalText fails.
+func unmarshal(v interface{}, b []byte, binary bool) error {
+	tu, isText := v.(encoding.TextUnmarshaler)
+	bu, isBinary := v.(encoding.BinaryUnmarshaler)
+
+	if isText {
+		err := tu.UnmarshalText(b)
+		if err == nil || !binary || !isBinary {
+			return err
+		}
+	}
+	if isBinary {
+		return bu.UnmarshalBinary(b)
+	}
+	return fmt.Errorf("cannot unmarshal text into %T", v)
+}
+
+// textValue returns the result of the MarshalText method of field as a
+// string. The type of field must satisfy isTextMarshalerType, or point to one
+// which does.
+func textValue(field reflect.Value) (interface{}, error) {
+	if field.Kind() == reflect.Ptr {
+		if field.IsNil() {
+			return nil, nil
+		}
+		field = field.Elem()
+	}
+
+	m, ok := field.Interface().(encoding.TextMarshaler)
+	if !ok {
+		// MarshalText has a pointer receiver
+		m = field.Addr().Interface().(encoding.TextMarshaler)
+	}
+	b, err := m.MarshalText()
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
@@ -0,0 +1,192 @@
+package scan_test
+
+import (
+	"database/sql/driver"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type textStatus int
+
+const (
+	textStatusActive textStatus = iota + 1
+	textStatusBanned
+)
+
+func (s textStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case textStatusActive:
+		return []byte("active"), nil
+	case textStatusBanned:
+		return []byte("banned"), nil
+	}
+	return nil, fmt.Errorf("invalid status %d", int(s))
+}
+
+func (s *textStatus) UnmarshalText(b []byte) error {
+	switch string(b) {
+	case "active":
+		*s = textStatusActive
+	case "banned":
+		*s = textStatusBanned
+	default:
+		return fmt.Errorf("invalid status %q", b)
+	}
+	return nil
+}
+
+// textAddr is a byte slice like net.IP, which raw bytes are copied into.
+type textAddr []byte
+
+func (a *textAddr) UnmarshalText(b []byte) error {
+	parts := strings.Split(string(b), ".")
+	addr := make(textAddr, len(parts))
+	for i, p := range parts {
+		if _, err := fmt.Sscanf(p, "%d", &addr[i]); err != nil {
+			return err
+		}
+	}
+	*a = addr
+	return nil
+}
+
+// textMoney only has pointer receivers and is a struct, which must not be
+// treated as a nested struct.
+type textMoney struct {
+	cents int64
+}
+
+func (m *textMoney) MarshalText() ([]byte, error) {
+	return []byte(fmt.Sprintf("%d.%02d", m.cents/100, m.cents%100)), nil
+}
+
+func (m *textMoney) UnmarshalText(b []byte) error {
+	var units, cents int64
+	if _, err := fmt.Sscanf(string(b), "%d.%d", &units, &cents); err != nil {
+		return err
+	}
+	m.cents = units*100 + cents
+	return nil
+}
+
+// textID is stored as text or as 4 raw bytes.
+type textID [4]byte
+
+func (id *textID) UnmarshalText(b []byte) error {
+	if len(b) != 8 {
+		return errors.New("invalid id")
+	}
+	_, err := hex.Decode(id[:], b)
+	return err
+}
+
+func (id *textID) UnmarshalBinary(b []byte) error {
+	if len(b) != 4 {
+		return errors.New("invalid id")
+	}
+	copy(id[:], b)
+	return nil
+}
+
+type textAccount struct {
+	ID      textID      `db:"id"`
+	Status  textStatus  `db:"status"`
+	Balance textMoney   `db:"balance"`
+	Limit   *textMoney  `db:"limit"`
+	Prev    *textStatus `db:"prev"`
+}
+
+func TestRowsScansTextUnmarshalers(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "status", "balance", "limit", "prev"},
+		[]interface{}{[]byte("0a0b0c0d"), "active", []byte("12.34"), "1.00", nil},
+		[]interface{}{[]byte{1, 2, 3, 4}, []byte("banned"), "0.50", nil, "active"},
+	)
+
+	var accounts []textAccount
+	require.NoError(t, scan.Rows(&accounts, rows))
+	active := textStatusActive
+	assert.Equal(t, []textAccount{
+		{ID: textID{10, 11, 12, 13}, Status: textStatusActive, Balance: textMoney{1234}, Limit: &textMoney{100}},
+		{ID: textID{1, 2, 3, 4}, Status: textStatusBanned, Balance: textMoney{50}, Prev: &active},
+	}, accounts)
+}
+
+func TestRowsScansTextUnmarshalerRows(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"status"},
+		[]interface{}{"banned"},
+		[]interface{}{"active"},
+	)
+
+	var statuses []textStatus
+	require.NoError(t, scan.Rows(&statuses, rows))
+	assert.Equal(t, []textStatus{textStatusBanned, textStatusActive}, statuses)
+}
+
+func TestRowsScansIntegersIntoTextUnmarshalers(t *testing.T) {
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"status", "prev"},
+		Rows: [][]driver.Value{{int64(2), int64(1)}, {"active", nil}},
+	})
+
+	var accounts []textAccount
+	require.NoError(t, scan.Rows(&accounts, rows))
+	banned, active := textStatusBanned, textStatusActive
+	assert.Equal(t, []textAccount{
+		{Status: banned, Prev: &active},
+		{Status: active},
+	}, accounts)
+
+	rows = queryTestDB(t, resultSet{
+		Cols: []string{"status"},
+		Rows: [][]driver.Value{{int64(1)}, {"banned"}},
+	})
+
+	var statuses []textStatus
+	require.NoError(t, scan.Rows(&statuses, rows))
+	assert.Equal(t, []textStatus{textStatusActive, textStatusBanned}, statuses)
+}
+
+func TestRowsCopiesBytesIntoByteSliceTextUnmarshalers(t *testing.T) {
+	rows := queryTestDB(t, resultSet{
+		Cols: []string{"addr"},
+		Rows: [][]driver.Value{{[]byte{10, 0, 0, 1}}, {"192.168.0.1"}},
+	})
+
+	var addrs []textAddr
+	require.NoError(t, scan.Rows(&addrs, rows))
+	assert.Equal(t, []textAddr{{10, 0, 0, 1}, {192, 168, 0, 1}}, addrs)
+}
+
+func TestRowReturnsUnmarshalTextErrors(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"status"},
+		[]interface{}{"deleted"},
+	)
+
+	var account textAccount
+	err := scan.Row(&account, rows)
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "Status", se.Field)
+	assert.True(t, strings.Contains(se.Err.Error(), `invalid status "deleted"`))
+}
+
+func TestColumnsIncludesTextFields(t *testing.T) {
+	cols, err := scan.Columns(&textAccount{})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"id", "status", "balance", "limit", "prev"}, cols)
+}
+
+func TestValuesUsesMarshalText(t *testing.T) {
+	account := textAccount{Status: textStatusBanned, Balance: textMoney{1205}}
+
+	vals, err := scan.Values([]string{"status", "balance", "limit", "prev"}, &account)
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"banned", "12.05", nil, nil}, vals)
+}
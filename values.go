@@ -1,8 +1,10 @@
 package scan
 
 import (
+	"encoding/json"
 	"fmt"
 	"reflect"
+	"strconv"
 	"sync"
 )
 
@@ -10,42 +12,141 @@ var valuesCache cache = &sync.Map{}
 
 // Values scans a struct and returns the values associated with the columns
 // provided. Only simple value types are supported (i.e. Bool, Ints, Uints,
-// Floats, Interface, String)
+// Floats, Interface, String), unless a converter is registered for the field
+// type with RegisterValueConverter. Fields tagged with the json option are
+// encoded as JSON strings and Postgres array fields as array literals. The
+// values of fields inside nil pointers to structs are nil. Columns without a
+// field are looked up in the field tagged with the rest option, if any. The
+// values of fields tagged with the nullzero option are nil when they hold
+// their zero value.
 func Values(cols []string, v interface{}) ([]interface{}, error) {
+	return std().Values(cols, v)
+}
+
+// Values scans a struct and returns the values associated with the columns
+// provided. See Values for details.
+func (s *Scanner) Values(cols []string, v interface{}) ([]interface{}, error) {
 	vals := make([]interface{}, len(cols))
 	model, err := reflectValue(v)
 	if err != nil {
 		return nil, fmt.Errorf("values: %w", err)
 	}
 
-	fields := loadFields(model)
+	fields := s.loadFields(model)
 
+	var seen map[string]int
+	if fields.duplicates {
+		seen = make(map[string]int, len(cols))
+	}
 	for i, col := range cols {
-		j, ok := fields[col]
+		f, ok := fields.columns[col]
+		if seen != nil {
+			seen[col]++
+			if g, found := fields.columns[occurrenceKey(col, seen[col])]; found {
+				f, ok = g, true
+			}
+		}
 		if !ok {
-			return nil, fmt.Errorf("field %T.%q either does not exist or is unexported: %w", v, col, ErrStructFieldMissing)
+			if val, found := restValue(model, fields.rest, col); found {
+				vals[i] = val
+				continue
+			}
+			return nil, &ScanError{
+				Row:    -1,
+				Column: col,
+				Type:   model.Type(),
+				Err:    fmt.Errorf("field either does not exist or is unexported: %w", ErrStructFieldMissing),
+			}
 		}
 
-		vals[i] = model.FieldByIndex(j).Interface()
+		field, err := model.FieldByIndexErr(f.index)
+		if err != nil {
+			// the field is inside a nil pointer to a struct
+			vals[i] = nil
+			continue
+		}
+		if f.nullZero && field.IsZero() {
+			vals[i] = nil
+			continue
+		}
+		if vals[i], err = f.value(field); err != nil {
+			return nil, &ScanError{Row: -1, Column: col, Field: fieldPath(model.Type(), f.index), Type: field.Type(), Err: err}
+		}
 	}
 	return vals, nil
 }
 
-func loadFields(val reflect.Value) map[string][]int {
-	if cache, cached := valuesCache.Load(val.Type()); cached {
-		return cache.(map[string][]int)
+// valueFields holds the fields Values reads the columns of a struct type
+// from.
+type valueFields struct {
+	// columns maps occurrence keys of column names to their fields
+	columns map[string]*valueField
+	// rest is the index of the field tagged with the rest option, if any
+	rest []int
+	// duplicates is true when a column name maps to more than one field, in
+	// which case Values counts the occurrences of the columns
+	duplicates bool
+}
+
+// valueField is a struct field Values reads a column from.
+type valueField struct {
+	index []int
+	// nullZero is true when the zero value of the field is written as NULL
+	nullZero bool
+	// value returns the column value of the field
+	value func(reflect.Value) (interface{}, error)
+}
+
+func (s *Scanner) loadFields(val reflect.Value) *valueFields {
+	if cache, cached := s.values.Load(val.Type()); cached {
+		return cache.(*valueFields)
+	}
+	return s.writeFieldsCache(val)
+}
+
+func (s *Scanner) writeFieldsCache(val reflect.Value) *valueFields {
+	typ := val.Type()
+	fields := &valueFields{
+		columns: map[string]*valueField{},
+		rest:    s.restField(typ),
+	}
+	byIndex := map[string]*valueField{}
+	for name, slots := range occurrences(s.writeFields(val, nil, []int{}, "", nil)) {
+		for i, index := range slots {
+			if index == nil {
+				continue
+			}
+			key := fmt.Sprint(index)
+			f, ok := byIndex[key]
+			if !ok {
+				f = s.valueField(typ, index)
+				byIndex[key] = f
+			}
+			fields.columns[occurrenceKey(name, i+1)] = f
+			fields.duplicates = fields.duplicates || i > 0
+		}
 	}
-	return writeFieldsCache(val)
+	s.values.Store(typ, fields)
+	return fields
 }
 
-func writeFieldsCache(val reflect.Value) map[string][]int {
-	m := map[string][]int{}
-	writeFields(val, m, []int{})
-	valuesCache.Store(val.Type(), m)
-	return m
+// valueField returns the field of typ at index along with how Values encodes
+// it.
+func (s *Scanner) valueField(typ reflect.Type, index []int) *valueField {
+	sf := structField(typ, index)
+	f := &valueField{index: index, nullZero: s.hasNullZeroTag(sf)}
+	switch {
+	case s.isJSON(sf):
+		f.value = jsonValue
+	case s.isArray(sf):
+		f.value = formatArray
+	default:
+		f.value = s.converters.valuer(sf.Type)
+	}
+	return f
 }
 
-func writeFields(val reflect.Value, m map[string][]int, index []int) {
+func (s *Scanner) writeFields(val reflect.Value, fields []taggedField, index []int, prefix string, hops []reflect.Type) []taggedField {
 	typ := val.Type()
 	numfield := val.NumField()
 
@@ -56,16 +157,71 @@ func writeFields(val reflect.Value, m map[string][]int, index []int) {
 		}
 
 		field := typ.Field(i)
-		fieldIndex := append(index, field.Index...)
+		fieldIndex := append(index[:len(index):len(index)], field.Index...)
+
+		isLeaf := s.converters.isLeaf(field.Type) || s.isJSON(field)
+
+		if field.Type.Kind() == reflect.Struct && !isValidSqlValue(valField) && !isLeaf {
+			childPrefix, _ := s.prefix(field)
+			fields = s.writeFields(valField, fields, fieldIndex, prefix+childPrefix, hops)
+			continue
+		}
 
-		if field.Type.Kind() == reflect.Struct && !isValidSqlValue(valField) {
-			writeFields(valField, m, fieldIndex)
+		if isPointerStruct(field.Type, hops) && !isLeaf {
+			childPrefix, _ := s.prefix(field)
+			elem := reflect.New(field.Type.Elem()).Elem()
+			fields = s.writeFields(elem, fields, fieldIndex, prefix+childPrefix, appendHop(hops, field.Type))
 			continue
 		}
 
-		m[field.Name] = fieldIndex
-		if tag, ok := field.Tag.Lookup(dbTag); ok {
-			m[tag] = fieldIndex
+		tag, hasTag := field.Tag.Lookup(s.cfg.TagName)
+		name, opts := parseTag(tag)
+		if hasTag && opts.Contains("rest") {
+			continue
+		}
+		fields = append(fields, taggedField{column: prefix + field.Name, index: fieldIndex})
+		if name != "" && name != field.Name {
+			fields = append(fields, taggedField{column: prefix + name, occurrence: opts.occurrence(), index: fieldIndex})
+		}
+	}
+	return fields
+}
+
+// jsonValue returns field encoded as a JSON string, or nil for nil pointers,
+// maps and slices.
+func jsonValue(field reflect.Value) (interface{}, error) {
+	switch field.Kind() {
+	case reflect.Ptr, reflect.Map, reflect.Slice, reflect.Interface:
+		if field.IsNil() {
+			return nil, nil
 		}
 	}
+	b, err := json.Marshal(field.Interface())
+	if err != nil {
+		return nil, err
+	}
+	return string(b), nil
+}
+
+// occurrenceKey returns the key of the nth occurrence of a column name in the
+// fields map. The first occurrence uses the name itself.
+func occurrenceKey(name string, n int) string {
+	if n <= 1 {
+		return name
+	}
+	return name + "\x00" + strconv.Itoa(n)
+}
+
+// restValue returns the value of col in the rest field of model at index,
+// which is false when model has no rest field or col is not in it.
+func restValue(model reflect.Value, index []int, col string) (interface{}, bool) {
+	if index == nil {
+		return nil, false
+	}
+	m := model.FieldByIndex(index)
+	val := m.MapIndex(reflect.ValueOf(col).Convert(m.Type().Key()))
+	if !val.IsValid() {
+		return nil, false
+	}
+	return val.Interface(), true
 }
@@ -161,7 +161,7 @@ func TestValuesReadsFromCacheFirst(t *testing.T) {
 	}
 
 	v := reflect.Indirect(reflect.ValueOf(&person)).Type()
-	valuesCache.Store(v, map[string][]int{"fake": {0}})
+	valuesCache.Store(v, &valueFields{columns: map[string]*valueField{"fake": {index: []int{0}, value: interfaceValue}}})
 
 	vals, err := Values([]string{"fake"}, &person)
 	require.NoError(t, err)
@@ -218,6 +218,69 @@ func TestValuesDriverValuerImplementers(t *testing.T) {
 	assert.EqualValues(t, []interface{}{"Brett", Pet{Name: "Mila", Species: "dog"}}, vals)
 }
 
+func TestValuesMapsDuplicateColumnsPositionally(t *testing.T) {
+	type company struct {
+		ID int `db:"id"`
+	}
+	type person struct {
+		ID      int `db:"id"`
+		Company company
+	}
+
+	p := &person{ID: 1, Company: company{ID: 2}}
+	vals, err := Values([]string{"id", "id"}, p)
+	require.NoError(t, err)
+	assert.EqualValues(t, []interface{}{1, 2}, vals)
+}
+
+func TestValuesMapsDuplicateColumnsToOuterFieldsFirst(t *testing.T) {
+	type company struct {
+		ID int `db:"id"`
+	}
+	type person struct {
+		Company company
+		ID      int `db:"id"`
+	}
+
+	p := &person{ID: 1, Company: company{ID: 2}}
+	vals, err := Values([]string{"id", "id"}, p)
+	require.NoError(t, err)
+	assert.EqualValues(t, []interface{}{1, 2}, vals)
+}
+
+func TestValuesMapsDuplicateColumnsByOccurrence(t *testing.T) {
+	type company struct {
+		ID int `db:"id,occurrence=2"`
+	}
+	type person struct {
+		Company company
+		ID      int `db:"id"`
+	}
+
+	p := &person{ID: 1, Company: company{ID: 2}}
+	vals, err := Values([]string{"id", "id"}, p)
+	require.NoError(t, err)
+	assert.EqualValues(t, []interface{}{1, 2}, vals)
+}
+
+func TestValuesReadsPointerStructs(t *testing.T) {
+	type company struct {
+		Name string `db:"name"`
+	}
+	type person struct {
+		ID      int      `db:"id"`
+		Company *company `db:"company_,prefix"`
+	}
+
+	vals, err := Values([]string{"id", "company_name"}, &person{ID: 1, Company: &company{Name: "Costco"}})
+	require.NoError(t, err)
+	assert.EqualValues(t, []interface{}{1, "Costco"}, vals)
+
+	vals, err = Values([]string{"id", "company_name"}, &person{ID: 1})
+	require.NoError(t, err)
+	assert.EqualValues(t, []interface{}{1, nil}, vals)
+}
+
 // benchmarks
 
 func BenchmarkValuesLargeStruct(b *testing.B) {
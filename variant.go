@@ -0,0 +1,191 @@
+package scan
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+)
+
+var (
+	// ErrUnknownVariant is returned when a row is scanned into an interface
+	// type and its discriminator column holds a value without a registered
+	// variant.
+	ErrUnknownVariant = errors.New("unknown variant")
+
+	// ErrNoVariants is returned when rows are scanned into an interface type
+	// which no registered variant implements.
+	ErrNoVariants = errors.New("no variants registered")
+
+	// ErrVariantColumns is returned when the variants implementing an
+	// interface type use different discriminator columns.
+	ErrVariantColumns = errors.New("variants use different discriminator columns")
+)
+
+// globalVariants holds the variants used by the package-level functions.
+// Scanners start with a copy of them.
+var globalVariants = &variants{}
+
+// RegisterVariant registers the type of proto as the variant of the rows
+// whose column holds value, so that rows can be scanned into an interface
+// type which is implemented by several structs:
+//
+//	scan.RegisterVariant("kind", "click", ClickEvent{})
+//	scan.RegisterVariant("kind", "view", &ViewEvent{})
+//
+//	var events []Event
+//	err := scan.Rows(&events, rows)
+//
+// The discriminator column of every row is scanned first and the row is then
+// scanned into a new struct of the type registered for its value, using the
+// normal mapping rules. Items are set to a struct or to a pointer to a
+// struct, like proto, or to a pointer when only the pointer type implements
+// the interface. Only the variants implementing the interface are
+// considered, and they must all use the same column. A later variant for
+// the same value replaces the earlier one. Values are compared
+// by their formatted value, so the string "1" matches the integer 1 and
+// []byte values match strings. A ScanError wrapping ErrUnknownVariant is
+// returned for values without a variant. RegisterVariant panics when proto
+// is not a struct or a pointer to a struct.
+//
+// Registered variants are used by the package-level functions and by the
+// Scanners created afterwards. Use Scanner.RegisterVariant to register a
+// variant for a single Scanner.
+func RegisterVariant(column string, value, proto interface{}) {
+	globalVariants.register(column, value, proto)
+}
+
+// RegisterVariant registers the type of proto as the variant of the rows
+// whose column holds value for s only. See RegisterVariant for details.
+func (s *Scanner) RegisterVariant(column string, value, proto interface{}) {
+	s.variants.register(column, value, proto)
+}
+
+// variants is a registry of the struct types that rows are scanned into
+// based on the value of a discriminator column.
+type variants struct {
+	mu   sync.RWMutex
+	list []variant
+}
+
+type variant struct {
+	column string
+	value  string
+	typ    reflect.Type
+}
+
+// clone returns a copy of v which can be changed independently.
+func (v *variants) clone() *variants {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	return &variants{list: append([]variant(nil), v.list...)}
+}
+
+func (v *variants) register(column string, value, proto interface{}) {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+
+	typ := reflect.TypeOf(proto)
+	if typ == nil || !isStructType(indirectType(typ)) {
+		panic(fmt.Sprintf("scan: variant %T must be a struct or a pointer to a struct", proto))
+	}
+	v.list = append(v.list, variant{column: column, value: variantKey(value), typ: typ})
+}
+
+// of returns the discriminator column and the types by value of the
+// variants which implement iface, or whose pointer type does.
+func (v *variants) of(iface reflect.Type) (string, map[string]reflect.Type, error) {
+	v.mu.RLock()
+	defer v.mu.RUnlock()
+
+	var column string
+	types := map[string]reflect.Type{}
+	for _, vr := range v.list {
+		typ := vr.typ
+		if !typ.Implements(iface) {
+			// a struct whose methods have pointer receivers is set as a
+			// pointer
+			if typ.Kind() == reflect.Ptr || !reflect.PointerTo(typ).Implements(iface) {
+				continue
+			}
+			typ = reflect.PointerTo(typ)
+		}
+		if column != "" && vr.column != column {
+			return "", nil, fmt.Errorf("%s: %q and %q: %w", iface, column, vr.column, ErrVariantColumns)
+		}
+		column = vr.column
+		types[vr.value] = typ
+	}
+	if column == "" {
+		return "", nil, fmt.Errorf("%s: %w", iface, ErrNoVariants)
+	}
+	return column, types, nil
+}
+
+// variantKey returns the string that discriminator values are compared by.
+func variantKey(value interface{}) string {
+	if b, ok := value.([]byte); ok {
+		return string(b)
+	}
+	return fmt.Sprint(value)
+}
+
+// isVariantType reports whether rows are scanned into t by variant, which is
+// the case for interface types other than interface{}.
+func isVariantType(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && t.NumMethod() > 0
+}
+
+// variantDecoder returns a decodeFunc which scans every row into the variant
+// of itemType registered for the value of its discriminator column. The
+// discriminator is scanned on its own first, which requires that r allows
+// Scan to be called more than once for the same row, as *sql.Rows does.
+func (s *Scanner) variantDecoder(r RowsScanner, itemType reflect.Type, cols []string, strict bool) (decodeFunc, error) {
+	column, types, err := s.variants.of(itemType)
+	if err != nil {
+		return nil, err
+	}
+	col := -1
+	for i, c := range cols {
+		if c == column {
+			col = i
+			break
+		}
+	}
+	if col < 0 {
+		return nil, fmt.Errorf("%s: discriminator column %q is not in the result: %w", itemType, column, ErrStructFieldMissing)
+	}
+
+	decoders := make(map[reflect.Type]decodeFunc, len(types))
+	return func(item reflect.Value) error {
+		var discard, value interface{}
+		dests := make([]interface{}, len(cols))
+		for i := range dests {
+			dests[i] = &discard
+		}
+		dests[col] = &value
+		if err := r.Scan(dests...); err != nil {
+			return &ScanError{Column: column, Type: itemType, Err: err}
+		}
+
+		typ, ok := types[variantKey(value)]
+		if !ok {
+			return &ScanError{Column: column, Type: itemType, Value: value, Err: fmt.Errorf("%v: %w", value, ErrUnknownVariant)}
+		}
+		decode, ok := decoders[typ]
+		if !ok {
+			if decode, err = s.decoder(r, indirectType(typ), cols, strict); err != nil {
+				return err
+			}
+			decoders[typ] = decode
+		}
+
+		v := reflect.New(typ).Elem()
+		if err := decode(allocIndirect(v)); err != nil {
+			return err
+		}
+		item.Set(v)
+		return nil
+	}, nil
+}
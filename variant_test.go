@@ -0,0 +1,127 @@
+package scan_test
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/blockloop/scan/v2"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type variantEvent interface {
+	EventID() int
+}
+
+type clickEvent struct {
+	ID     int    `db:"id"`
+	Kind   string `db:"kind"`
+	Button string `db:"button"`
+}
+
+func (e clickEvent) EventID() int { return e.ID }
+
+type viewEvent struct {
+	ID   int    `db:"id"`
+	Page string `db:"page"`
+}
+
+func (e *viewEvent) EventID() int { return e.ID }
+
+func variantScanner() *scan.Scanner {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterVariant("kind", "click", clickEvent{})
+	s.RegisterVariant("kind", "view", &viewEvent{})
+	return s
+}
+
+func TestRowsScansVariants(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "kind", "button", "page"},
+		[]interface{}{1, "click", "left", nil},
+		[]interface{}{2, []byte("view"), nil, "/home"},
+	)
+
+	var events []variantEvent
+	require.NoError(t, variantScanner().Rows(&events, rows))
+	assert.Equal(t, []variantEvent{
+		clickEvent{ID: 1, Kind: "click", Button: "left"},
+		&viewEvent{ID: 2, Page: "/home"},
+	}, events)
+}
+
+func TestRowScansVariant(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "kind", "page"},
+		[]interface{}{2, "view", "/about"},
+	)
+
+	var event variantEvent
+	require.NoError(t, variantScanner().Row(&event, rows))
+	assert.Equal(t, &viewEvent{ID: 2, Page: "/about"}, event)
+}
+
+func TestRowsErrorsOnUnknownVariant(t *testing.T) {
+	rows := fakeRowsWithRecords(t, []string{"id", "kind"},
+		[]interface{}{1, "scroll"},
+	)
+
+	var events []variantEvent
+	err := variantScanner().Rows(&events, rows)
+	assert.True(t, errors.Is(err, scan.ErrUnknownVariant))
+	var se *scan.ScanError
+	require.True(t, errors.As(err, &se))
+	assert.Equal(t, "kind", se.Column)
+	assert.Equal(t, "scroll", se.Value)
+	assert.Equal(t, 0, se.Row)
+}
+
+func TestRowsVariantErrors(t *testing.T) {
+	var events []variantEvent
+	err := variantScanner().Rows(&events, fakeRowsWithColumns(t, 1, "id"))
+	assert.True(t, errors.Is(err, scan.ErrStructFieldMissing))
+
+	err = scan.New(scan.DefaultConfig()).Rows(&events, fakeRowsWithColumns(t, 1, "id", "kind"))
+	assert.True(t, errors.Is(err, scan.ErrNoVariants))
+
+	s := variantScanner()
+	s.RegisterVariant("type", "scroll", clickEvent{})
+	err = s.Rows(&events, fakeRowsWithColumns(t, 1, "id", "kind"))
+	assert.True(t, errors.Is(err, scan.ErrVariantColumns))
+}
+
+type pointerEvent interface {
+	SetSource(string)
+}
+
+func (e *clickEvent) SetSource(string) {}
+
+func TestRowsScansValueVariantsOfPointerReceiverInterfaces(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterVariant("kind", "click", clickEvent{})
+
+	rows := fakeRowsWithRecords(t, []string{"id", "kind", "button"},
+		[]interface{}{1, "click", "left"},
+	)
+
+	var events []pointerEvent
+	require.NoError(t, s.Rows(&events, rows))
+	assert.Equal(t, []pointerEvent{&clickEvent{ID: 1, Kind: "click", Button: "left"}}, events)
+}
+
+func TestRegisterVariantMatchesFormattedValues(t *testing.T) {
+	s := scan.New(scan.DefaultConfig())
+	s.RegisterVariant("type", 1, clickEvent{})
+
+	rows := fakeRowsWithRecords(t, []string{"id", "type"},
+		[]interface{}{5, int64(1)},
+	)
+
+	var events []variantEvent
+	require.NoError(t, s.Rows(&events, rows))
+	assert.Equal(t, []variantEvent{clickEvent{ID: 5}}, events)
+}
+
+func TestRegisterVariantPanicsForNonStructs(t *testing.T) {
+	assert.Panics(t, func() {
+		scan.New(scan.DefaultConfig()).RegisterVariant("kind", "x", 42)
+	})
+}